@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var AddTask = &cobra.Command{
+	Use:   "add [title]",
+	Short: "Add a task to the board",
+	Long: `Adds a task with the given title on top of the backlog column of the board of the
+current directory. With --template, the task starts from the template with that name,
+see "gotasks template". The title can be left out if the title of the template doesn't
+need one.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		templateName, _ := cmd.Flags().GetString("template")
+		description, _ := cmd.Flags().GetString("description")
+		
+		userConfig, boardOpt, err := domain.GetBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the board. %s", err)
+		}
+		
+		if boardOpt.IsNone() {
+			fmt.Println("There's no board for this directory.")
+			fmt.Println("Run \"gotasks\" to create one.")
+			os.Exit(1)
+		}
+		
+		board := boardOpt.Unwrap()
+		
+		title := ""
+		if len(args) > 0 {
+			title = args[0]
+		}
+		
+		task := domain.NewTask(title, description)
+		
+		if templateName != "" {
+			template, err := userConfig.FindTemplate(board, templateName)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			
+			template.ApplyTo(task)
+		}
+		
+		if task.Title == "" {
+			fmt.Println("A task has to have a title.")
+			os.Exit(1)
+		}
+		
+		err = userConfig.AddTask(board.Id, task)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		
+		fmt.Printf("Added %s \"%s\".\n", board.GetTaskKey(task), task.Title)
+	},
+}
+
+func init() {
+	AddTask.Flags().StringP("template", "t", "", "The name of the template the task starts from")
+	AddTask.Flags().StringP("description", "d", "", "The description of the task")
+}
@@ -0,0 +1,39 @@
+package archive
+
+import (
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var ListArchivedTasks = &cobra.Command{
+	Use:   "list",
+	Short: "List the archived tasks of the board",
+	Long:  `List the archived tasks of the board of the current directory, the last one archived first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, board := cmdutil.GetBoardForCurrentDir()
+		
+		archive := board.GetShelf(domain.ArchiveShelf)
+		
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"#", "Key", "Task", "Column", "Archived at"})
+		
+		for i := len(archive) - 1; i >= 0; i-- {
+			t.AppendRow([]any{
+				len(archive) - i,
+				board.GetTaskKey(archive[i].Task),
+				archive[i].Task.Title,
+				board.GetColumnName(archive[i].Column),
+				utils.FormatTime(archive[i].RemovedAt),
+			})
+		}
+		t.AppendSeparator()
+		
+		t.Render()
+	},
+}
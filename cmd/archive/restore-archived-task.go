@@ -0,0 +1,35 @@
+package archive
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var RestoreArchivedTask = &cobra.Command{
+	Use:   "restore <task key>",
+	Short: "Put an archived task back on the board",
+	Long: `Puts an archived task of the board of the current directory back in the column
+it was archived from. The task is given by its key, like API-42, or by the start of its ID.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		removed, err := board.FindRemovedTask(domain.ArchiveShelf, args[0])
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("Run \"gotasks archive list\" to view all archived tasks.")
+			return
+		}
+		
+		err = userConfig.RestoreTask(board.Id, removed.Task.Id)
+		if err != nil {
+			log.Fatalf("Failed to restore the task. %s", err)
+		}
+		
+		fmt.Printf("Restored \"%s\".\n", removed.Task.Title)
+	},
+}
@@ -0,0 +1,55 @@
+package archive
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var ArchiveCmd = &cobra.Command{
+	Use:   "archive [task key]",
+	Short: "Archive the done and cancelled tasks of the board",
+	Long: `Takes every task in the done and the cancelled columns of the board of the current
+directory off the board into its archive. Passing the key of one of those tasks
+archives only that task. Archived tasks can be listed and restored through the subcommands.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			archiveTask(args[0])
+			return
+		}
+		
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		count, err := userConfig.ArchiveClosedTasks(board.Id)
+		if err != nil {
+			log.Fatalf("Failed to archive the closed tasks. %s", err)
+		}
+		
+		fmt.Printf("Archived %d done and cancelled tasks.\n", count)
+	},
+}
+
+func archiveTask(taskId string) {
+	userConfig, _, err := domain.FindBoardForCurrentDir()
+	if err != nil {
+		log.Fatalf("Failed to get the user config. %s", err)
+	}
+	
+	board, task, err := userConfig.FindTask(taskId)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	
+	err = userConfig.ArchiveTask(board.Id, task)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	
+	fmt.Printf("Archived \"%s\".\n", task.Title)
+}
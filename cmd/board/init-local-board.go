@@ -0,0 +1,54 @@
+package board
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var InitLocalBoard = &cobra.Command{
+	Use:   "init",
+	Short: "Create a board inside the current directory that can be committed",
+	Long: `Creates a board in .gotasks/board.json inside the current directory. Running gotasks
+in this directory, or any directory under it, opens this board over any board
+saved in the config. Commit the file to share the board with everyone working on the project.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pwd, err := os.Getwd()
+		if err != nil {
+			log.Fatalf("Failed to get the current directory. %s", err)
+		}
+		
+		var fromBoard *domain.Board
+		
+		fromBoardName, _ := cmd.Flags().GetString("from")
+		if fromBoardName != "" {
+			config, err := domain.GetUserConfig()
+			if err != nil {
+				log.Fatalf("Failed to get the user config. %s", err)
+			}
+			
+			boardOpt := config.GetBoard(fromBoardName)
+			if boardOpt.IsNone() {
+				fmt.Println("Couldn't find the board to copy from.")
+				fmt.Println("Run \"gotasks list\" to view all available boards.")
+				return
+			}
+			
+			fromBoard = boardOpt.Unwrap()
+		}
+		
+		filePath, err := domain.CreateLocalBoard(pwd, fromBoard)
+		if err != nil {
+			log.Fatalf("Failed to create the local board. %s", err)
+		}
+		
+		fmt.Printf("Created the board at %s.\n", filePath)
+	},
+}
+
+func init() {
+	InitLocalBoard.Flags().String("from", "", "Name of a board in the config to copy the columns and tasks from")
+}
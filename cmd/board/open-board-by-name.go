@@ -24,23 +24,17 @@ var OpenBoardByName= &cobra.Command{
 		
 		config, err := domain.GetUserConfig()
 		if err != nil {
-			log.Fatalf("Failed to get a userConfig instance. ", err)
+			log.Fatalf("Failed to get a userConfig instance. %s", err)
 		}
 		
-		for _, board := range config.Boards {
-			if board.Name == boardName {
-				userConfig, err := domain.GetUserConfig()
-				if err != nil {
-					log.Fatalf("Failed to setup the user config. %s", err)
-				}
-				
-				app, err := ui.NewApp(userConfig, boardName)
-				if err != nil {
-					log.Fatalf("Failed to initialize app. %v", err)
-				}
-				app.Run()
-				break
+		entryOpt := config.GetBoardEntry(boardName)
+		if entryOpt.IsSome() {
+			app, err := ui.NewApp(config, entryOpt.Unwrap().Id)
+			if err != nil {
+				log.Fatalf("Failed to initialize app. %v", err)
 			}
+			app.Run()
+			return
 		}
 		
 		fmt.Println("Couldn't find the board you tried to open.")
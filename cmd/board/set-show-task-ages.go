@@ -0,0 +1,65 @@
+package board
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var SetShowTaskAges = &cobra.Command{
+	Use:   "ages [on|off]",
+	Short: "Show or change if the cards of the board show how old their tasks are",
+	Long: `Cards can show how old their tasks are, like "3d", or how long ago they were done
+for the ones in a done column, like "done 2h ago".
+Without arguments, this shows if the board of the current directory shows them.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, boardOpt, err := domain.GetBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the board. %s", err)
+		}
+		
+		if boardOpt.IsNone() {
+			fmt.Println("There's no board for this directory.")
+			fmt.Println("Run \"gotasks\" to create one.")
+			os.Exit(1)
+		}
+		
+		board := boardOpt.Unwrap()
+		
+		if len(args) == 0 {
+			if board.ShowTaskAges {
+				fmt.Println("on")
+			} else {
+				fmt.Println("off")
+			}
+			return
+		}
+		
+		var show bool
+		switch args[0] {
+		case "on":
+			show = true
+		case "off":
+			show = false
+		default:
+			fmt.Printf("Unknown setting \"%s\". It could be \"on\" or \"off\"\n", args[0])
+			return
+		}
+		
+		err = userConfig.SetShowTaskAges(board.Id, show)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		if show {
+			fmt.Printf("The cards of \"%s\" now show how old their tasks are.\n", board.Name)
+		} else {
+			fmt.Printf("The cards of \"%s\" no longer show how old their tasks are.\n", board.Name)
+		}
+	},
+}
@@ -0,0 +1,65 @@
+package board
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var SetSwimlanes = &cobra.Command{
+	Use:   "lanes [label|assignee|priority|epic|none]",
+	Short: "Show or change what the board is split into swimlanes by",
+	Long: `Swimlanes split the tasks of a board into horizontal lanes that cross all of its
+columns, one for every label, assignee, priority or epic the tasks have.
+Without arguments, this shows what the board of the current directory is split by.
+Passing "none" shows the board without lanes. Changing it can't be undone.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, boardOpt, err := domain.GetBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the board. %s", err)
+		}
+		
+		if boardOpt.IsNone() {
+			fmt.Println("There's no board for this directory.")
+			fmt.Println("Run \"gotasks\" to create one.")
+			os.Exit(1)
+		}
+		
+		board := boardOpt.Unwrap()
+		
+		if len(args) == 0 {
+			if board.Swimlanes == domain.NoSwimlanes {
+				fmt.Println("none")
+				return
+			}
+			
+			fmt.Println(board.Swimlanes)
+			for _, lane := range board.GetSwimlanes() {
+				fmt.Printf("  %s\n", lane.Name)
+			}
+			return
+		}
+		
+		grouping, err := domain.ParseSwimlaneGrouping(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		err = userConfig.SetSwimlanes(board.Id, grouping)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		if grouping == domain.NoSwimlanes {
+			fmt.Printf("\"%s\" is no longer split into swimlanes.\n", board.Name)
+		} else {
+			fmt.Printf("\"%s\" is now split into swimlanes by %s.\n", board.Name, grouping)
+		}
+	},
+}
@@ -0,0 +1,47 @@
+package board
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var SetTaskKeyPrefix = &cobra.Command{
+	Use:   "prefix [prefix]",
+	Short: "Show or change the prefix of the task keys of the board",
+	Long: `Every task gets a key made of the prefix of its board and a number, like API-42.
+Without arguments, this shows the prefix of the board of the current directory.
+Passing a prefix changes it for every task of the board. The numbers stay as they are.
+Changing the prefix can't be undone.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, boardOpt, err := domain.GetBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the board. %s", err)
+		}
+		
+		if boardOpt.IsNone() {
+			fmt.Println("There's no board for this directory.")
+			fmt.Println("Run \"gotasks\" to create one.")
+			os.Exit(1)
+		}
+		
+		board := boardOpt.Unwrap()
+		
+		if len(args) == 0 {
+			fmt.Println(board.TaskKeyPrefix)
+			return
+		}
+		
+		err = userConfig.SetTaskKeyPrefix(board.Id, args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("Tasks of \"%s\" are now keyed like %s-%d.\n", board.Name, board.TaskKeyPrefix, max(board.LastTaskNumber, 1))
+	},
+}
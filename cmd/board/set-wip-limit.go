@@ -0,0 +1,104 @@
+package board
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var SetWipLimit = &cobra.Command{
+	Use:   "wip [column] [limit]",
+	Short: "Show or change the work-in-progress limits of the board",
+	Long: `Without arguments, this shows the WIP limit of every column of the board of the
+current directory. Passing a column and a limit sets the limit of that column, and
+a limit of 0 removes it. Changes to the limits can't be undone.
+
+Moving a task into a column that's at its limit asks for a confirmation first. With
+--strict, such moves are refused instead. --soft goes back to asking.`,
+	Args: cobra.MatchAll(
+		cobra.MaximumNArgs(2),
+		func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return fmt.Errorf("Pass the limit of the column along with it")
+			}
+			return nil
+		},
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, boardOpt, err := domain.GetBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the board. %s", err)
+		}
+		
+		if boardOpt.IsNone() {
+			fmt.Println("There's no board for this directory.")
+			fmt.Println("Run \"gotasks\" to create one.")
+			os.Exit(1)
+		}
+		
+		board := boardOpt.Unwrap()
+		
+		strict, _ := cmd.Flags().GetBool("strict")
+		soft, _ := cmd.Flags().GetBool("soft")
+		if strict || soft {
+			err = userConfig.SetStrictWipLimits(board.Id, strict)
+			if err != nil {
+				log.Fatalf("Failed to save the board. %s", err)
+			}
+		}
+		
+		if len(args) == 2 {
+			limit, err := strconv.Atoi(args[1])
+			if err != nil {
+				fmt.Printf("The limit \"%s\" should be a number.\n", args[1])
+				return
+			}
+			
+			column, err := board.FindColumn(args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			
+			err = userConfig.SetWipLimit(board.Id, column.Id, limit)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		
+		fmt.Printf("WIP limits of \"%s\" (%s):\n", board.Name, utils.Cond(board.StrictWipLimits, "strict", "soft"))
+		
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"Column", "Tasks", "Limit"})
+		
+		for _, column := range board.Columns {
+			limit := "-"
+			if column.WipLimit > 0 {
+				limit = strconv.Itoa(column.WipLimit)
+			}
+			
+			t.AppendRow([]any{
+				column.Name,
+				len(board.Tasks[column.Id]),
+				limit,
+			})
+		}
+		t.AppendSeparator()
+		
+		t.Render()
+	},
+}
+
+func init() {
+	SetWipLimit.Flags().Bool("strict", false, "Refuse moving a task into a column that's at its limit")
+	SetWipLimit.Flags().Bool("soft", false, "Ask before moving a task into a column that's at its limit")
+	SetWipLimit.MarkFlagsMutuallyExclusive("strict", "soft")
+}
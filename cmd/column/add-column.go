@@ -0,0 +1,44 @@
+package column
+
+import (
+	"fmt"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var AddColumn = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a column to the board",
+	Long: `Adds a column with the given name to the board of the current directory. It's
+added to the right of the other columns, unless a position is given with --at,
+where 1 is the left most column. New columns are active unless a role is given
+with --role.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		position, _ := cmd.Flags().GetInt("at")
+		roleText, _ := cmd.Flags().GetString("role")
+		
+		role, err := domain.ParseColumnRole(roleText)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		column, err := userConfig.AddColumn(board.Id, args[0], role, position - 1)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("Added the column \"%s\".\n", column.Name)
+	},
+}
+
+func init() {
+	AddColumn.Flags().Int("at", 0, "The position to add the column at, starting from 1")
+	AddColumn.Flags().String("role", string(domain.ActiveRole), "The role of the column: backlog, active, done or cancelled")
+}
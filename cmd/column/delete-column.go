@@ -0,0 +1,48 @@
+package column
+
+import (
+	"fmt"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var DeleteColumn = &cobra.Command{
+	Use:   "delete <column>",
+	Short: "Delete a column of the board",
+	Long: `Deletes a column of the board of the current directory. If it has tasks, the column
+to move them to has to be given with --move-to.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		column := findColumn(board, args[0])
+		tasksCount := len(board.Tasks[column.Id])
+		
+		moveToId := ""
+		if moveToName, _ := cmd.Flags().GetString("move-to"); moveToName != "" {
+			moveToId = findColumn(board, moveToName).Id
+		}
+
+		if tasksCount > 0 && moveToId == "" {
+			fmt.Printf("\"%s\" has %d tasks, pass --move-to with the column to move them to.\n", column.Name, tasksCount)
+			return
+		}
+
+		err := userConfig.DeleteColumn(board.Id, column.Id, moveToId)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		if tasksCount > 0 {
+			fmt.Printf("Deleted \"%s\" and moved its %d tasks to \"%s\".\n", column.Name, tasksCount, board.GetColumnName(moveToId))
+		} else {
+			fmt.Printf("Deleted \"%s\".\n", column.Name)
+		}
+	},
+}
+
+func init() {
+	DeleteColumn.Flags().String("move-to", "", "The column to move the tasks of the deleted column to")
+}
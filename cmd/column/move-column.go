@@ -0,0 +1,36 @@
+package column
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var MoveColumn = &cobra.Command{
+	Use:   "move <column> <position>",
+	Short: "Move a column of the board to another position",
+	Long: `Moves a column of the board of the current directory to the given position, where
+1 is the left most column. Its tasks move along with it.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		column := findColumn(board, args[0])
+		
+		position, err := strconv.Atoi(args[1])
+		if err != nil || position < 1 {
+			fmt.Printf("The position \"%s\" should be a number starting from 1.\n", args[1])
+			return
+		}
+		
+		err = userConfig.MoveColumn(board.Id, column.Id, position - 1)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("Moved \"%s\".\n", column.Name)
+	},
+}
@@ -0,0 +1,29 @@
+package column
+
+import (
+	"fmt"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var RenameColumn = &cobra.Command{
+	Use:   "rename <column> <new name>",
+	Short: "Rename a column of the board",
+	Long: `Renames a column of the board of the current directory. Its tasks stay in it.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		column := findColumn(board, args[0])
+		oldName := column.Name
+		
+		err := userConfig.RenameColumn(board.Id, column.Id, args[1])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("Renamed \"%s\" to \"%s\".\n", oldName, column.Name)
+	},
+}
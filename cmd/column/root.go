@@ -0,0 +1,65 @@
+package column
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var ColumnCmd = &cobra.Command{
+	Use:   "column",
+	Short: "List the columns of the board",
+	Long: `Lists the columns of the board of the current directory, from left to right. They
+can be added, renamed, moved around and deleted through the subcommands. Columns
+are given by their name, which isn't case sensitive. The role of a column says what
+being in it means for a task, like being done with it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, board := cmdutil.GetBoardForCurrentDir()
+		
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"#", "Column", "Role", "Tasks", "WIP limit", "Color"})
+		
+		for i, column := range board.Columns {
+			limit := "-"
+			if column.WipLimit > 0 {
+				limit = strconv.Itoa(column.WipLimit)
+			}
+			
+			color := "-"
+			if column.Color != "" {
+				color = column.Color
+			}
+			
+			t.AppendRow([]any{
+				i + 1,
+				column.Name,
+				column.Role,
+				len(board.Tasks[column.Id]),
+				limit,
+				color,
+			})
+		}
+		t.AppendSeparator()
+		
+		t.Render()
+	},
+}
+
+// findColumn returns the column of the board with the given name, exiting if it has none.
+func findColumn(board *domain.Board, name string) *domain.Column {
+	column, err := board.FindColumn(name)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("Run \"gotasks board column\" to view all columns.")
+		os.Exit(1)
+	}
+	
+	return column
+}
@@ -0,0 +1,37 @@
+package column
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var SetColumnColor = &cobra.Command{
+	Use:   "color <column> <color>",
+	Short: "Set the color of a column of the board",
+	Long: fmt.Sprintf(`Sets the color the header of a column of the board of the current directory is
+shown in. The color is one of %s, or "none" to go back to the
+primary color.`, strings.Join(domain.ColumnColors, ", ")),
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		column := findColumn(board, args[0])
+		
+		color := args[1]
+		if strings.EqualFold(color, "none") {
+			color = ""
+		}
+		
+		err := userConfig.SetColumnColor(board.Id, column.Id, color)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("Set the color of \"%s\".\n", column.Name)
+	},
+}
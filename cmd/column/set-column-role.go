@@ -0,0 +1,40 @@
+package column
+
+import (
+	"fmt"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var SetColumnRole = &cobra.Command{
+	Use:   "role <column> <role>",
+	Short: "Set the role of a column of the board",
+	Long: `Sets what being in a column of the board of the current directory means for a task.
+The role is one of:
+  backlog    Tasks that weren't started yet. New tasks go to the first backlog column.
+  active     Tasks being worked on.
+  done       Finished tasks. They count towards the progress of the board.
+  cancelled  Tasks that won't be done. They don't count towards the progress.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		column := findColumn(board, args[0])
+		
+		role, err := domain.ParseColumnRole(args[1])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		err = userConfig.SetColumnRole(board.Id, column.Id, role)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("Set the role of \"%s\" to %s.\n", column.Name, role)
+	},
+}
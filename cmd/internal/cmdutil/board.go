@@ -0,0 +1,43 @@
+// Package cmdutil holds what the commands of gotasks share.
+package cmdutil
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/domain"
+)
+
+// GetBoardForCurrentDir returns the board of the current directory, exiting if it has none.
+func GetBoardForCurrentDir() (*domain.UserConfig, *domain.Board) {
+	userConfig, board := GetBoardForCurrentDirIfAny()
+	if board == nil {
+		ExitWithoutBoard("Run \"gotasks\" to create one.")
+	}
+	
+	return userConfig, board
+}
+
+// GetBoardForCurrentDirIfAny returns the board of the current directory, which is nil
+// if it has none, for the commands that also work outside of a board.
+func GetBoardForCurrentDirIfAny() (*domain.UserConfig, *domain.Board) {
+	userConfig, boardOpt, err := domain.GetBoardForCurrentDir()
+	if err != nil {
+		log.Fatalf("Failed to get the board. %s", err)
+	}
+	
+	if boardOpt.IsNone() {
+		return userConfig, nil
+	}
+	
+	return userConfig, boardOpt.Unwrap()
+}
+
+// ExitWithoutBoard says the current directory has no board, along with the given hint
+// on what to do about it, and exits.
+func ExitWithoutBoard(hint string) {
+	fmt.Println("There's no board for this directory.")
+	fmt.Println(hint)
+	os.Exit(1)
+}
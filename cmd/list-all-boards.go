@@ -24,17 +24,24 @@ var ListAllBoards = &cobra.Command{
 		t.SetOutputMirror(os.Stdout)
 		t.AppendHeader(table.Row{"#", "Board", "Number of tasks", "Progress", "Path"})
 		
-		for i, board := range config.Boards {
-			totalNumberOfTasks := 0
-			numberOfCompletedTasks := 0
-			for i, columnName := range board.Columns {
-				totalNumberOfTasks += len(board.Tasks[columnName])
+		for i, entry := range config.Boards {
+			// A board that can't be read shouldn't hide the rest of them.
+			board, err := config.LoadBoard(entry)
+			if err != nil {
+				t.AppendRow([]any{
+					i + 1,
+					entry.Name,
+					"-",
+					"Failed to read",
+					entry.Dir,
+				})
 				
-				if i == len(board.Columns) - 1 { // Check if we are on the last column to the right.
-					numberOfCompletedTasks += len(board.Tasks[columnName])					
-				}
+				continue
 			}
 			
+			// Cancelled tasks don't count towards the progress.
+			numberOfCompletedTasks, totalNumberOfTasks := board.GetProgress()
+			
 			progress := float32(0)
 			if totalNumberOfTasks != 0 {
 				progress = float32(numberOfCompletedTasks) / float32(totalNumberOfTasks)
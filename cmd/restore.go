@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var Restore = &cobra.Command{
+	Use:   "restore [number]",
+	Short: "List the backups or roll back to one of them",
+	Long: `Lists the backups kept of the config and of every board. Passing the number
+of a backup from the list rolls the file it was taken of back to it. A backup taken
+before gotasks was upgraded is upgraded as it's restored.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := domain.GetStoreBasedOnEnv()
+		if err != nil {
+			log.Fatalf("Failed to get the store. %s", err)
+		}
+
+		backupStore, ok := store.(domain.BackupStore)
+		if !ok {
+			printWhyBackupsCantBeRestored(store)
+			return
+		}
+
+		backups, err := backupStore.ListBackups()
+		if err != nil {
+			log.Fatalf("Failed to list the backups. %s", err)
+		}
+
+		if len(args) == 0 {
+			printBackups(backups)
+			return
+		}
+
+		number, err := strconv.Atoi(args[0])
+		if err != nil || number < 1 || number > len(backups) {
+			fmt.Println("Please provide the number of a backup from the list.")
+			fmt.Println("Run \"gotasks restore\" to view all available backups.")
+			return
+		}
+
+		backup := backups[number - 1]
+
+		err = backupStore.RestoreBackup(backup)
+		if err != nil {
+			log.Fatalf("Failed to restore the backup. %s", err)
+		}
+
+		fmt.Printf("Restored %s to how it was at %s.\n", backup.FileName, backup.Time.Local().Format("2006-01-02 15:04:05"))
+	},
+}
+
+// printWhyBackupsCantBeRestored tells where the backups of a store that can't list or
+// restore them are, if it keeps any.
+func printWhyBackupsCantBeRestored(store domain.Store) {
+	sqliteStore, ok := store.(*domain.SQLiteStore)
+	if !ok {
+		fmt.Println("No backups are kept when boards are stored in memory.")
+		return
+	}
+
+	fmt.Println("Backups of the SQLite database can't be listed or restored through gotasks.")
+	fmt.Printf("A copy of the whole database is kept in %s before every upgrade of gotasks.\n", sqliteStore.GetBackupsDirPath())
+	fmt.Printf("To restore one, close every gotasks and copy it over %s by hand.\n", sqliteStore.GetFilePath())
+}
+
+func printBackups(backups []*domain.Backup) {
+	// The config is only needed to show board names. Backups should still be listed
+	// if it's the config that needs restoring.
+	boardNames := map[string]string{}
+	config, err := domain.GetUserConfig()
+	if err == nil {
+		for _, entry := range config.Boards {
+			boardNames[entry.Id] = entry.Name
+		}
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "File", "Board", "Taken at"})
+
+	for i, backup := range backups {
+		t.AppendRow([]any{
+			i + 1,
+			backup.FileName,
+			boardNames[backup.BoardId],
+			backup.Time.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	t.AppendSeparator()
+
+	t.Render()
+}
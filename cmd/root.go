@@ -3,12 +3,16 @@ package cmd
 import (
 	"log"
 	"os"
-	"runtime"
+	"path/filepath"
 
+	"github.com/okira-e/gotasks/cmd/archive"
 	"github.com/okira-e/gotasks/cmd/board"
+	"github.com/okira-e/gotasks/cmd/column"
+	"github.com/okira-e/gotasks/cmd/task"
+	"github.com/okira-e/gotasks/cmd/template"
+	"github.com/okira-e/gotasks/cmd/trash"
 	"github.com/okira-e/gotasks/internal/domain"
 	"github.com/okira-e/gotasks/internal/ui"
-	"github.com/okira-e/gotasks/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -20,49 +24,9 @@ gotasks is a kanban board in the TUI. Think of it as a tool that sits
 between a to-do list and a Jira board that is accessible from the terminal.
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var userConfig *domain.UserConfig
+		userConfig, boardId := resolveBoardForCurrentDir()
 		
-		userConfig, err := domain.GetUserConfig()
-		if err != nil {
-			log.Fatalf("Failed to get the user config. %s", err)
-		}
-		
-		// Assume this is not a new board/board.
-		// Traverse the current file path until you find a directory with the name of
-		// board saved in the config. If not found, create one at the current directory.
-		
-		originalPwd, err := os.Getwd()
-		if err != nil {
-			log.Fatalf("Failed to get the current directory. %s", err)
-		}
-		
-		os := runtime.GOOS
-		
-		var boardName string
-		yeildedPwd := originalPwd
-		for yeildedPwd != "" {
-			var currentDirName string
-
-			yeildedPwd, currentDirName = getLastDirName(yeildedPwd, byte(utils.Cond(os == "windows", '\\', '/')))
-			if currentDirName == "" {
-				log.Fatalf("Failed to get the directory name.")
-			}
-			
-			boardOpt := userConfig.GetBoard(currentDirName)
-			if boardOpt.IsSome() {
-				boardName = currentDirName
-				break
-			} else {
-				continue
-			}
-		}
-		
-		if boardName == "" {
-			_, boardName = getLastDirName(originalPwd, byte(utils.Cond(os == "windows", '\\', '/')))
-			userConfig.CreateBoard(boardName, originalPwd)
-		}
-		
-		app, err := ui.NewApp(userConfig, boardName)
+		app, err := ui.NewApp(userConfig, boardId)
 		if err != nil {
 			log.Fatalf("Failed to initialize app. %v", err)
 		}
@@ -70,14 +34,78 @@ between a to-do list and a Jira board that is accessible from the terminal.
 	},
 }
 
+// resolveBoardForCurrentDir finds the board to open for the current directory,
+// and creates one for it if none was found. It returns the user config the board
+// is in, along with the board's ID.
+func resolveBoardForCurrentDir() (*domain.UserConfig, string) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("Failed to get the current directory. %s", err)
+	}
+	
+	// Boards are found by the directory they were created in. The nearest one up
+	// from the current directory wins.
+	userConfig, boardIdOpt, err := domain.FindBoardForDir(pwd)
+	if err != nil {
+		log.Fatalf("Failed to get the user config. %s", err)
+	}
+	
+	if boardIdOpt.IsSome() {
+		return userConfig, boardIdOpt.Unwrap()
+	}
+	
+	board, err := userConfig.CreateBoard(filepath.Base(pwd), pwd)
+	if err != nil {
+		log.Fatalf("Failed to create a board. %s", err)
+	}
+	
+	return userConfig, board.Id
+}
+
 func Execute() {
 	rootCmd.AddCommand(Version)
 	rootCmd.AddCommand(ListAllBoards)
 	rootCmd.AddCommand(OpenConfig)
 	rootCmd.AddCommand(OpenLogs)
+	rootCmd.AddCommand(Restore)
+	rootCmd.AddCommand(AddTask)
 	rootCmd.AddCommand(board.BoardCmd)
+	rootCmd.AddCommand(task.TaskCmd)
+	rootCmd.AddCommand(archive.ArchiveCmd)
+	rootCmd.AddCommand(trash.TrashCmd)
+	rootCmd.AddCommand(template.TemplateCmd)
 	
 	board.BoardCmd.AddCommand(board.OpenBoardByName)
+	board.BoardCmd.AddCommand(board.InitLocalBoard)
+	board.BoardCmd.AddCommand(board.SetTaskKeyPrefix)
+	board.BoardCmd.AddCommand(board.SetWipLimit)
+	board.BoardCmd.AddCommand(board.SetSwimlanes)
+	board.BoardCmd.AddCommand(board.SetShowTaskAges)
+	board.BoardCmd.AddCommand(column.ColumnCmd)
+	
+	column.ColumnCmd.AddCommand(column.AddColumn)
+	column.ColumnCmd.AddCommand(column.RenameColumn)
+	column.ColumnCmd.AddCommand(column.MoveColumn)
+	column.ColumnCmd.AddCommand(column.DeleteColumn)
+	column.ColumnCmd.AddCommand(column.SetColumnColor)
+	column.ColumnCmd.AddCommand(column.SetColumnRole)
+	
+	task.TaskCmd.AddCommand(task.ShowTaskLog)
+	task.TaskCmd.AddCommand(task.ShowTaskDeps)
+	task.TaskCmd.AddCommand(task.BlockTask)
+	task.TaskCmd.AddCommand(task.UnblockTask)
+	task.TaskCmd.AddCommand(task.AddTaskComment)
+	task.TaskCmd.AddCommand(task.SetTaskRecurrence)
+	task.TaskCmd.AddCommand(task.MoveTaskToBoard)
+	
+	archive.ArchiveCmd.AddCommand(archive.ListArchivedTasks)
+	archive.ArchiveCmd.AddCommand(archive.RestoreArchivedTask)
+	
+	trash.TrashCmd.AddCommand(trash.EmptyTrash)
+	trash.TrashCmd.AddCommand(trash.RestoreTrashedTask)
+	
+	template.TemplateCmd.AddCommand(template.AddTemplate)
+	template.TemplateCmd.AddCommand(template.DeleteTemplate)
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -85,20 +113,3 @@ func Execute() {
 	}
 }
 
-
-// getLastDirName takes in "/Users/You/Projects/Todo" and returns ("/Users/You/Projects", "Todo").
-// Returns an empty string if no "/" was found in the path.
-func getLastDirName(path string, pathSeparator byte) (string, string) {
-	// Windows
-	if path[1:3] == ":\\" {
-		path = path[2:]
-	}
-	
-	for i := len(path) - 1; i >= 0; i-- {
-		if path[i] == pathSeparator {
-			return path[:i], path[i+1:]
-		}
-	}
-	
-	return "", ""
-}
\ No newline at end of file
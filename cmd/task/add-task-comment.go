@@ -0,0 +1,45 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var AddTaskComment = &cobra.Command{
+	Use:   "comment <task key> <text>",
+	Short: "Leave a comment on a task",
+	Long: `Adds a comment to a task, like a status update, without touching its description.
+The comment is left by the user.name of git, or by the user logged in if git has
+none, unless another author is given.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, _, err := domain.FindBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the user config. %s", err)
+		}
+		
+		board, task, err := userConfig.FindTask(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		author, _ := cmd.Flags().GetString("author")
+		
+		comment, err := userConfig.AddComment(board.Id, task, author, strings.Join(args[1:], " "))
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("Commented on %s as %s.\n", board.GetTaskKey(task), comment.Author)
+	},
+}
+
+func init() {
+	AddTaskComment.Flags().String("author", "", "Who the comment is from, instead of the user.name of git")
+}
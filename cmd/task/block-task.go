@@ -0,0 +1,70 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var BlockTask = &cobra.Command{
+	Use:   "block <task key> <blocker key>",
+	Short: "Mark a task as blocked by another task",
+	Long: `Marks the first task as blocked by the second one, which has to be on the same
+board. A task can't be blocked by a task that's already blocked by it.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board, task, blocker := findTaskAndBlocker(args[0], args[1])
+		
+		err := userConfig.AddBlocker(board.Id, task, blocker)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("\"%s\" is now blocked by \"%s\".\n", task.Title, blocker.Title)
+	},
+}
+
+var UnblockTask = &cobra.Command{
+	Use:   "unblock <task key> <blocker key>",
+	Short: "Mark a task as no longer blocked by another task",
+	Long:  `Marks the first task as no longer blocked by the second one.`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board, task, blocker := findTaskAndBlocker(args[0], args[1])
+		
+		err := userConfig.RemoveBlocker(board.Id, task, blocker.Id)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("\"%s\" is no longer blocked by \"%s\".\n", task.Title, blocker.Title)
+	},
+}
+
+// findTaskAndBlocker finds the task with the first ID and the task on the same board
+// with the second one, exiting if either isn't found.
+func findTaskAndBlocker(taskId string, blockerId string) (*domain.UserConfig, *domain.Board, *domain.Task, *domain.Task) {
+	userConfig, _, err := domain.FindBoardForCurrentDir()
+	if err != nil {
+		log.Fatalf("Failed to get the user config. %s", err)
+	}
+	
+	board, task, err := userConfig.FindTask(taskId)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	
+	blocker, err := board.FindTaskOnBoard(blockerId)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	
+	return userConfig, board, task, blocker
+}
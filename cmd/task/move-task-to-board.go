@@ -0,0 +1,67 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var MoveTaskToBoard = &cobra.Command{
+	Use:   "move <task key> --board <board name>",
+	Short: "Move or copy a task to another board",
+	Long: `Moves the task on top of the backlog column of the other board, where it gets a
+key of its own. It keeps its details, its checklist, its comments and its history,
+which records where it went. With --copy, the task stays where it is and a copy of it
+is added to the other board instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		boardName, _ := cmd.Flags().GetString("board")
+		isCopy, _ := cmd.Flags().GetBool("copy")
+		
+		userConfig, _, err := domain.FindBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the user config. %s", err)
+		}
+		
+		board, task, err := userConfig.FindTask(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		
+		targetOpt := userConfig.GetBoard(boardName)
+		if targetOpt.IsNone() {
+			fmt.Printf("Couldn't find a board named \"%s\". Run \"gotasks list\" to list them.\n", boardName)
+			os.Exit(1)
+		}
+		target := targetOpt.Unwrap()
+		
+		key := board.GetTaskKey(task)
+		
+		var added *domain.Task
+		if isCopy {
+			added, err = userConfig.CopyTaskToBoard(board, task, target)
+		} else {
+			added, err = userConfig.MoveTaskToBoard(board, task, target)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		
+		fmt.Printf("%s %s to \"%s\" as %s.\n", utils.Cond(isCopy, "Copied", "Moved"), key, target.Name, target.GetTaskKey(added))
+		if !isCopy {
+			fmt.Printf("Undoing the move on \"%s\" puts it back there without taking it off \"%s\".\n", board.Name, target.Name)
+		}
+	},
+}
+
+func init() {
+	MoveTaskToBoard.Flags().String("board", "", "The name of the board to move the task to")
+	MoveTaskToBoard.Flags().Bool("copy", false, "Copy the task rather than moving it")
+	MoveTaskToBoard.MarkFlagRequired("board")
+}
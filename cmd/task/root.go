@@ -0,0 +1,11 @@
+package task
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var TaskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Perform an operation on a specific task",
+	Long:  `Perform an operation on a specific task.`,
+}
@@ -0,0 +1,60 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var SetTaskRecurrence = &cobra.Command{
+	Use:   "repeat <task key> [daily | weekly | monthly | every <n> days | none]",
+	Short: "Show or set how often a task comes back",
+	Long: `Shows how often a task comes back, or sets it. Daily, weekly and monthly tasks come
+back on a schedule, going by their due date. Tasks repeated every n days come back n days
+after they're done. "none" stops the task from coming back.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, _, err := domain.FindBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the user config. %s", err)
+		}
+
+		board, task, err := userConfig.FindTask(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(args) == 1 {
+			if task.Recurrence == nil {
+				fmt.Printf("\"%s\" doesn't repeat.\n", task.Title)
+			} else {
+				fmt.Printf("\"%s\" repeats %s.\n", task.Title, task.Recurrence)
+			}
+
+			return
+		}
+
+		recurrence, err := domain.ParseRecurrence(strings.Join(args[1:], " "))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		err = userConfig.SetTaskRecurrence(board.Id, task, recurrence)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if recurrence == nil {
+			fmt.Printf("\"%s\" no longer repeats.\n", task.Title)
+		} else {
+			fmt.Printf("\"%s\" now repeats %s.\n", task.Title, recurrence)
+		}
+	},
+}
@@ -0,0 +1,82 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var ShowTaskDeps = &cobra.Command{
+	Use:   "deps <task key>",
+	Short: "Show the tasks a task is blocked by",
+	Long: `Prints the tree of the tasks a task is blocked by, along with the tasks those
+are blocked by in turn, followed by the tasks it blocks. The task is given by its
+key, like API-42, or by its ID.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, _, err := domain.FindBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the user config. %s", err)
+		}
+		
+		board, task, err := userConfig.FindTask(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		l := list.NewWriter()
+		l.SetOutputMirror(os.Stdout)
+		l.SetStyle(list.StyleConnectedLight)
+		
+		l.AppendItem(describeTaskDependency(board, task))
+		l.Indent()
+		appendBlockers(l, board, task, map[string]bool{task.Id: true})
+		l.Render()
+		
+		dependents := board.GetDependents(task)
+		if len(dependents) > 0 {
+			fmt.Println()
+			fmt.Println("Blocks:")
+			
+			for _, it := range dependents {
+				fmt.Printf("  %s\n", describeTaskDependency(board, it))
+			}
+		}
+	},
+}
+
+// appendBlockers adds the blockers of the task to the list, each one with its own
+// blockers under it. Tasks already on the way down aren't added again, in case a
+// merge made a cycle.
+func appendBlockers(l list.Writer, board *domain.Board, task *domain.Task, visited map[string]bool) {
+	for _, blocker := range board.GetBlockers(task) {
+		if visited[blocker.Id] {
+			l.AppendItem(describeTaskDependency(board, blocker) + " (cycle)")
+			continue
+		}
+		
+		l.AppendItem(describeTaskDependency(board, blocker))
+		
+		visited[blocker.Id] = true
+		l.Indent()
+		appendBlockers(l, board, blocker, visited)
+		l.UnIndent()
+		delete(visited, blocker.Id)
+	}
+}
+
+func describeTaskDependency(board *domain.Board, task *domain.Task) string {
+	column, _ := board.GetColumnForTask(task)
+	
+	state := column.Name
+	if board.IsBlocked(task) {
+		state += ", blocked"
+	}
+	
+	return fmt.Sprintf("%s  %s  [%s]", board.GetTaskKey(task), task.Title, state)
+}
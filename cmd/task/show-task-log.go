@@ -0,0 +1,54 @@
+package task
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var ShowTaskLog = &cobra.Command{
+	Use:   "log <task key>",
+	Short: "Show the history of a task",
+	Long: `Shows everything that happened to a task, like when it was created and every
+column it was moved to, oldest first. The task is given by its key, like API-42,
+which is shown on its card. Its ID works too, and the start of the ID is enough as
+long as no other task shares it.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// The board of the current directory might be a local one, which isn't in
+		// the user config on its own.
+		userConfig, _, err := domain.FindBoardForCurrentDir()
+		if err != nil {
+			log.Fatalf("Failed to get the user config. %s", err)
+		}
+		
+		board, task, err := userConfig.FindTask(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("%s %s (%s)\n", board.GetTaskKey(task), task.Title, board.Name)
+		
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"#", "When", "Event"})
+		
+		for i, event := range task.GetEvents() {
+			t.AppendRow([]any{
+				i + 1,
+				utils.FormatTime(event.At),
+				event.String(),
+			})
+		}
+		t.AppendSeparator()
+		
+		t.Render()
+	},
+}
+
@@ -0,0 +1,58 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var AddTemplate = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a template tasks can start from",
+	Long: `Adds a template with the given name to the board of the current directory, or
+for every board with --global. A template with the same name is replaced.
+
+The title is a pattern like "Bug: {title}", where "{title}" is replaced by the title
+typed for the task and "{date}" by the date of the day.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		global, _ := cmd.Flags().GetBool("global")
+		title, _ := cmd.Flags().GetString("title")
+		description, _ := cmd.Flags().GetString("description")
+		labels, _ := cmd.Flags().GetString("labels")
+		checklist, _ := cmd.Flags().GetStringArray("checklist")
+		
+		userConfig, board := cmdutil.GetBoardForCurrentDirIfAny()
+		if board == nil && !global {
+			cmdutil.ExitWithoutBoard(noBoardHint)
+		}
+		
+		template := domain.NewTaskTemplate(args[0])
+		template.Title = title
+		template.Description = description
+		template.Labels = domain.ParseLabels(labels)
+		template.Checklist = checklist
+		
+		err := userConfig.SaveTemplate(board, template, global)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		if global {
+			fmt.Printf("Added the global template \"%s\".\n", template.Name)
+		} else {
+			fmt.Printf("Added the template \"%s\" to \"%s\".\n", template.Name, board.Name)
+		}
+	},
+}
+
+func init() {
+	AddTemplate.Flags().Bool("global", false, "Add the template for every board rather than the board of the current directory")
+	AddTemplate.Flags().String("title", "", "The pattern of the title, like \"Bug: {title}\"")
+	AddTemplate.Flags().String("description", "", "The description tasks start with")
+	AddTemplate.Flags().String("labels", "", "The labels tasks start with, separated by commas")
+	AddTemplate.Flags().StringArray("checklist", []string{}, "An item of the checklist tasks start with. Can be passed more than once")
+}
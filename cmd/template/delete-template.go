@@ -0,0 +1,36 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var DeleteTemplate = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a template",
+	Long: `Deletes the template with the given name from the board of the current directory,
+or from the global ones with --global. The tasks made from it are left as they are.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		global, _ := cmd.Flags().GetBool("global")
+		
+		userConfig, board := cmdutil.GetBoardForCurrentDirIfAny()
+		if board == nil && !global {
+			cmdutil.ExitWithoutBoard(noBoardHint)
+		}
+		
+		err := userConfig.DeleteTemplate(board, args[0], global)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		
+		fmt.Printf("Deleted the template \"%s\".\n", args[0])
+	},
+}
+
+func init() {
+	DeleteTemplate.Flags().Bool("global", false, "Delete a global template rather than one of the board of the current directory")
+}
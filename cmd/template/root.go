@@ -0,0 +1,53 @@
+package template
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var TemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "List the templates tasks can start from",
+	Long: `Lists the templates the tasks of the board of the current directory can start
+from: the ones of the board, then the global ones. A board template takes the place
+of a global one with the same name. Outside of a board, only the global ones are listed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDirIfAny()
+		
+		templates := userConfig.Templates
+		if board != nil {
+			templates = userConfig.GetTemplates(board)
+		}
+		
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"Name", "Of", "Title", "Labels", "Checklist items"})
+		
+		for _, template := range templates {
+			of := "global"
+			if board != nil && board.IsBoardTemplate(template) {
+				of = "board"
+			}
+			
+			t.AppendRow([]any{
+				template.Name,
+				of,
+				template.Title,
+				strings.Join(template.Labels, ", "),
+				len(template.Checklist),
+			})
+		}
+		t.AppendSeparator()
+		
+		t.Render()
+	},
+}
+
+// noBoardHint is what the commands that change the templates of the board say to do
+// outside of a board.
+const noBoardHint = "Run \"gotasks\" to create one, or pass --global for a template of every board."
@@ -0,0 +1,27 @@
+package trash
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+var EmptyTrash = &cobra.Command{
+	Use:   "empty",
+	Short: "Empty the trash",
+	Long: `Takes every task out of the trash of the board of the current directory.
+Like any other change, it can be undone from the board with "u", which puts them back in the trash.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		count, err := userConfig.EmptyTrash(board.Id)
+		if err != nil {
+			log.Fatalf("Failed to empty the trash. %s", err)
+		}
+		
+		fmt.Printf("Took %d tasks out of the trash. Press \"u\" on the board to undo it.\n", count)
+	},
+}
@@ -0,0 +1,35 @@
+package trash
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var RestoreTrashedTask = &cobra.Command{
+	Use:   "restore <task key>",
+	Short: "Put a deleted task back on the board",
+	Long: `Puts a deleted task of the board of the current directory back in the column
+it was deleted from. The task is given by its key, like API-42, or by the start of its ID.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userConfig, board := cmdutil.GetBoardForCurrentDir()
+		
+		removed, err := board.FindRemovedTask(domain.TrashShelf, args[0])
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("Run \"gotasks trash\" to view all deleted tasks.")
+			return
+		}
+		
+		err = userConfig.RestoreTask(board.Id, removed.Task.Id)
+		if err != nil {
+			log.Fatalf("Failed to restore the task. %s", err)
+		}
+		
+		fmt.Printf("Restored \"%s\".\n", removed.Task.Title)
+	},
+}
@@ -0,0 +1,41 @@
+package trash
+
+import (
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/okira-e/gotasks/cmd/internal/cmdutil"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var TrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List the deleted tasks of the board",
+	Long: `Lists the tasks deleted from the board of the current directory, the last one
+deleted first. They can be restored, or the trash emptied, through the subcommands.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, board := cmdutil.GetBoardForCurrentDir()
+		
+		trash := board.GetShelf(domain.TrashShelf)
+		
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"#", "Key", "Task", "Column", "Deleted at"})
+		
+		for i := len(trash) - 1; i >= 0; i-- {
+			t.AppendRow([]any{
+				len(trash) - i,
+				board.GetTaskKey(trash[i].Task),
+				trash[i].Task.Title,
+				board.GetColumnName(trash[i].Column),
+				utils.FormatTime(trash[i].RemovedAt),
+			})
+		}
+		t.AppendSeparator()
+		
+		t.Render()
+	},
+}
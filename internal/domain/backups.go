@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// backupsToKeep is how many old versions of every file are kept around.
+const backupsToKeep = 10
+
+// backupTimeLayout is the timestamp added to the name of a backup. It sorts in time order.
+const backupTimeLayout = "20060102-150405.000000"
+
+// Backup is an old version of a file in the store that can be restored.
+type Backup struct {
+	// Path is where the backup itself is.
+	Path string
+	// FileName is the name of the file this is a backup of, like "config.json".
+	FileName string
+	// BoardId is set when this is a backup of a board file.
+	BoardId string
+	Time    time.Time
+}
+
+// BackupStore is a store that keeps old versions of what it saves.
+type BackupStore interface {
+	Store
+	// ListBackups returns every backup kept, newest first.
+	ListBackups() ([]*Backup, error)
+	// RestoreBackup rolls the file the backup was taken of back to it.
+	RestoreBackup(backup *Backup) error
+}
+
+// writeFile backs up the file at the given path, if there's one, then replaces
+// it atomically with the given content.
+func (self *JSONStore) writeFile(filePath string, content []byte) error {
+	err := self.backupFile(filePath)
+	if err != nil {
+		return fmt.Errorf("Failed to back up %s. %s", filepath.Base(filePath), err)
+	}
+
+	return utils.WriteFileAtomic(filePath, content, 0644)
+}
+
+// backupFile copies the current version of the file to the backups folder and
+// removes the backups of it that are beyond the ones to keep.
+func (self *JSONStore) backupFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(self.backupsDirPath, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	fileName := filepath.Base(filePath)
+	name := strings.TrimSuffix(fileName, ".json")
+	backupPath := filepath.Join(
+		self.backupsDirPath,
+		name + "." + time.Now().UTC().Format(backupTimeLayout) + ".json",
+	)
+
+	err = utils.WriteFileAtomic(backupPath, content, 0644)
+	if err != nil {
+		return err
+	}
+
+	backups, err := self.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	for _, backup := range backups {
+		if backup.FileName != fileName {
+			continue
+		}
+
+		kept += 1
+		if kept > backupsToKeep {
+			err = os.Remove(backup.Path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// backupAll copies the config and every board file to a folder of their own
+// in the backups folder.
+func (self *JSONStore) backupAll(label string) error {
+	backupDirPath := filepath.Join(
+		self.backupsDirPath,
+		label + "." + time.Now().UTC().Format(backupTimeLayout),
+	)
+
+	err := os.MkdirAll(filepath.Join(backupDirPath, "boards"), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	filePaths := []string{self.configFilePath}
+
+	boardFilePaths, err := filepath.Glob(filepath.Join(self.boardsDirPath, "*.json"))
+	if err != nil {
+		return err
+	}
+	filePaths = append(filePaths, boardFilePaths...)
+
+	for _, filePath := range filePaths {
+		content, err := os.ReadFile(filePath)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(filepath.Dir(self.configFilePath), filePath)
+		if err != nil {
+			return err
+		}
+
+		err = utils.WriteFileAtomic(filepath.Join(backupDirPath, relativePath), content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (self *JSONStore) ListBackups() ([]*Backup, error) {
+	entries, err := os.ReadDir(self.backupsDirPath)
+	if os.IsNotExist(err) {
+		return []*Backup{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ret := []*Backup{}
+
+	for _, entry := range entries {
+		// Backups are named like "<name>.<time>.json".
+		parts := strings.Split(strings.TrimSuffix(entry.Name(), ".json"), ".")
+		if entry.IsDir() || len(parts) < 3 {
+			continue
+		}
+
+		name := strings.Join(parts[:len(parts)-2], ".")
+		backupTime, err := time.Parse(backupTimeLayout, strings.Join(parts[len(parts)-2:], "."))
+		if err != nil {
+			continue
+		}
+
+		backup := new(Backup)
+		backup.Path = filepath.Join(self.backupsDirPath, entry.Name())
+		backup.FileName = name + ".json"
+		backup.Time = backupTime
+		if backup.FileName != filepath.Base(self.configFilePath) {
+			backup.BoardId = name
+		}
+
+		ret = append(ret, backup)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Time.After(ret[j].Time)
+	})
+
+	return ret, nil
+}
+
+// RestoreBackup takes the lock on the store so no other process saves in between.
+// A board backed up before an upgrade of gotasks is migrated to the current schema
+// version as it's restored. A config is migrated the next time it's read, like any
+// config written by an older version.
+func (self *JSONStore) RestoreBackup(backup *Backup) error {
+	unlock, err := self.Lock()
+	if err != nil {
+		return fmt.Errorf("Failed to lock the store on restoring a backup. %s", err)
+	}
+	defer unlock()
+
+	content, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return err
+	}
+
+	var filePath string
+	if backup.BoardId == "" {
+		filePath = self.configFilePath
+	} else {
+		filePath = self.getBoardFilePath(backup.BoardId)
+
+		content, err = migrateBoardBackup(content)
+		if err != nil {
+			return fmt.Errorf("Failed to migrate the backup to the current version of gotasks. %s", err)
+		}
+	}
+
+	before, statErr := os.Stat(filePath)
+
+	// The version being replaced gets backed up too, so a restore can be undone.
+	err = self.writeFile(filePath, content)
+	if err != nil {
+		return err
+	}
+
+	// The revision goes by the time the file was written and its size, which can both
+	// stay the same when a backup of the same size is restored right after a save.
+	// Running instances have to see the change to reload.
+	if statErr == nil {
+		after, err := os.Stat(filePath)
+		if err == nil && after.Size() == before.Size() && !after.ModTime().After(before.ModTime()) {
+			modTime := before.ModTime().Add(time.Millisecond)
+			return os.Chtimes(filePath, modTime, modTime)
+		}
+	}
+
+	return nil
+}
+
+// migrateBoardBackup returns the content of a board backup at the current schema version.
+func migrateBoardBackup(content []byte) ([]byte, error) {
+	version, err := persistedSchemaVersion(content)
+	if err != nil || version == CurrentSchemaVersion {
+		return content, err
+	}
+
+	board := document{}
+	err = json.Unmarshal(content, &board)
+	if err != nil {
+		return nil, err
+	}
+
+	err = migrateBoardDocument(board)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(board, "", "\t")
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJSONStoreRestoreBackup(t *testing.T) {
+	store := NewJSONStore(t.TempDir())
+
+	err := store.SaveConfig(NewDefaultUserConfig())
+	if err != nil {
+		t.Fatalf("Failed to save the config. %s", err)
+	}
+
+	board := newTestBoard("board", "before")
+	err = store.SaveBoard(board)
+	if err != nil {
+		t.Fatalf("Failed to save the board. %s", err)
+	}
+
+	// Same size as "before", so only the time the file was written tells them apart.
+	board.Tasks[board.Columns[0].Id][0].Title = "after!"
+	err = store.SaveBoard(board)
+	if err != nil {
+		t.Fatalf("Failed to save the board again. %s", err)
+	}
+
+	backups, err := store.ListBackups()
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("Expected a single backup of the board, got %d, %v", len(backups), err)
+	}
+
+	revision, err := store.Revision(board.Id)
+	if err != nil {
+		t.Fatalf("Failed to get the revision. %s", err)
+	}
+
+	// Another process holds the lock for a while. The restore waits for it.
+	unlock, err := store.Lock()
+	if err != nil {
+		t.Fatalf("Failed to lock the store. %s", err)
+	}
+
+	restored := make(chan error)
+	go func() {
+		restored <- store.RestoreBackup(backups[0])
+	}()
+
+	select {
+	case <-restored:
+		t.Fatalf("Expected the restore to wait for the lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+
+	err = <-restored
+	if err != nil {
+		t.Fatalf("Failed to restore the backup. %s", err)
+	}
+
+	loaded, err := store.LoadBoard(board.Id)
+	if err != nil {
+		t.Fatalf("Failed to load the board. %s", err)
+	}
+
+	if title := loaded.Tasks[board.Columns[0].Id][0].Title; title != "before" {
+		t.Errorf("Expected the task to be back to \"before\", got \"%s\"", title)
+	}
+
+	newRevision, _ := store.Revision(board.Id)
+	if newRevision == revision {
+		t.Errorf("Expected restoring the backup to change the revision of the board")
+	}
+}
+
+func TestJSONStoreRestoreBackupFromBeforeAnUpgrade(t *testing.T) {
+	configDirPath := t.TempDir()
+	store := NewJSONStore(configDirPath)
+
+	legacyConfig := `{
+		"boards": [{
+			"name": "api",
+			"dir": "/work/api",
+			"columns": ["Todo", "Done"],
+			"tasks": {
+				"Todo": [{"id": "1", "title": "Todo", "created_at": "2024-01-01T00:00:00Z"}],
+				"Done": [{"id": "2", "title": "Done", "created_at": "2024-01-02T00:00:00Z"}]
+			}
+		}]
+	}`
+	err := os.WriteFile(filepath.Join(configDirPath, "config.json"), []byte(legacyConfig), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write the config. %s", err)
+	}
+
+	config, err := GetUserConfigFromStore(store)
+	if err != nil {
+		t.Fatalf("Failed to migrate the config. %s", err)
+	}
+	board := findTestBoard(t, config, "api")
+
+	backups, err := store.ListBackups()
+	if err != nil {
+		t.Fatalf("Failed to list the backups. %s", err)
+	}
+
+	// The oldest backup of the board is from before most of the upgrades.
+	var oldest *Backup
+	for _, backup := range backups {
+		if backup.BoardId == board.Id {
+			oldest = backup
+		}
+	}
+	if oldest == nil {
+		t.Fatalf("Expected the board to be backed up while it was migrated")
+	}
+
+	err = store.RestoreBackup(oldest)
+	if err != nil {
+		t.Fatalf("Failed to restore the backup. %s", err)
+	}
+
+	saved, err := store.loadBoardDocument(board.Id)
+	if err != nil {
+		t.Fatalf("Failed to read the restored board. %s", err)
+	}
+	if version := documentSchemaVersion(saved); version != CurrentSchemaVersion {
+		t.Errorf("Expected the board to be restored at schema version %d, got %d", CurrentSchemaVersion, version)
+	}
+
+	restored := findTestBoard(t, reopenConfig(t, config), "api")
+	if len(restored.Columns) != 2 || restored.Columns[1].Role != DoneRole {
+		t.Fatalf("Expected the restored board to have its columns, got %+v", restored.Columns)
+	}
+	for i, title := range []string{"Todo", "Done"} {
+		tasks := restored.Tasks[restored.Columns[i].Id]
+		if len(tasks) != 1 || tasks[0].Title != title || restored.GetTaskKey(tasks[0]) != fmt.Sprintf("API-%d", i + 1) {
+			t.Errorf("Expected %s to be back in its column with its key", title)
+		}
+	}
+}
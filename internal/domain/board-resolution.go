@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/okira-e/gotasks/internal/opt"
+)
+
+// FindBoardForDir looks for the board of the given directory. It walks up from
+// the directory and the nearest directory that is the Dir of a board wins. A local
+// board file (see GetLocalBoardFilePath) wins over a board in the config at the
+// same directory.
+// It returns the user config the board is in and the board's ID, or None if no
+// board was found, in which case the returned config is the user's.
+func FindBoardForDir(dirPath string) (*UserConfig, opt.Option[string], error) {
+	userConfig, err := GetUserConfig()
+	if err != nil {
+		return nil, opt.None[string](), err
+	}
+
+	currentDir := filepath.Clean(dirPath)
+	for {
+		localBoardOpt := GetLocalBoardFilePath(currentDir)
+		if localBoardOpt.IsSome() {
+			localUserConfig, boardId, err := GetUserConfigWithLocalBoard(localBoardOpt.Unwrap())
+			if err != nil {
+				return nil, opt.None[string](), fmt.Errorf("Failed to open the local board. %s", err)
+			}
+
+			return localUserConfig, opt.Some(boardId), nil
+		}
+
+		entryOpt := userConfig.GetBoardEntryByDir(currentDir)
+		if entryOpt.IsSome() {
+			return userConfig, opt.Some(entryOpt.Unwrap().Id), nil
+		}
+
+		parentDir := filepath.Dir(currentDir)
+		if parentDir == currentDir {
+			break
+		}
+		currentDir = parentDir
+	}
+
+	return userConfig, opt.None[string](), nil
+}
+
+// FindBoardForCurrentDir is FindBoardForDir for the current directory.
+func FindBoardForCurrentDir() (*UserConfig, opt.Option[string], error) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, opt.None[string](), fmt.Errorf("Failed to get the current directory. %s", err)
+	}
+
+	return FindBoardForDir(pwd)
+}
+
+// GetBoardForCurrentDir returns the board of the current directory, or None if it
+// has none, along with the user config it's in.
+func GetBoardForCurrentDir() (*UserConfig, opt.Option[*Board], error) {
+	userConfig, boardIdOpt, err := FindBoardForCurrentDir()
+	if err != nil {
+		return nil, opt.None[*Board](), err
+	}
+
+	if boardIdOpt.IsNone() {
+		return userConfig, opt.None[*Board](), nil
+	}
+
+	entryOpt := userConfig.GetBoardEntryById(boardIdOpt.Unwrap())
+	if entryOpt.IsNone() {
+		return userConfig, opt.None[*Board](), nil
+	}
+
+	board, err := userConfig.LoadBoard(entryOpt.Unwrap())
+	if err != nil {
+		return nil, opt.None[*Board](), err
+	}
+
+	return userConfig, opt.Some(board), nil
+}
+
+// GetBoardEntryByDir searches the index for the board whose directory is exactly the given one.
+func (self *UserConfig) GetBoardEntryByDir(dirPath string) opt.Option[*BoardEntry] {
+	dirPath = filepath.Clean(dirPath)
+
+	for _, it := range self.Boards {
+		if it.Dir != "" && filepath.Clean(it.Dir) == dirPath {
+			return opt.Some(it)
+		}
+	}
+
+	return opt.None[*BoardEntry]()
+}
+
+// disambiguateBoardName returns a name for a board in the given directory that isn't
+// taken. The parent directories are added in front of the name one by one, so a
+// second "api" board becomes something like "personal/api". A number is added
+// at the end as a last resort.
+func disambiguateBoardName(name string, dirPath string, isTaken func(string) bool) string {
+	if !isTaken(name) {
+		return name
+	}
+
+	candidate := name
+	parentDir := filepath.Dir(filepath.Clean(dirPath))
+	for dirPath != "" && parentDir != filepath.Dir(parentDir) {
+		candidate = filepath.Base(parentDir) + "/" + candidate
+		if !isTaken(candidate) {
+			return candidate
+		}
+
+		parentDir = filepath.Dir(parentDir)
+	}
+
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)", name, i)
+		if !isTaken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// migrateUniqueBoardNames renames boards that share their name with a board before
+// them in the index, since names can't be used to tell boards apart anymore.
+func migrateUniqueBoardNames(store documentStore, config document) error {
+	taken := map[string]bool{}
+	boards, _ := config["boards"].([]any)
+
+	for _, it := range boards {
+		entry, ok := it.(document)
+		if !ok {
+			continue
+		}
+
+		name, _ := entry["name"].(string)
+		dir, _ := entry["dir"].(string)
+		boardId, _ := entry["id"].(string)
+
+		newName := disambiguateBoardName(name, dir, func(candidate string) bool {
+			return taken[candidate]
+		})
+		taken[newName] = true
+
+		if newName == name {
+			continue
+		}
+
+		entry["name"] = newName
+
+		board, err := store.loadBoardDocument(boardId)
+		if err != nil {
+			// The index is what names are shown from. The board file catches up
+			// the next time it's saved.
+			continue
+		}
+
+		board["name"] = newName
+
+		err = store.saveBoardDocument(boardId, board)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MoveTaskToBoard moves the task on top of the backlog column of the other board,
+// where it gets a key of its own. It keeps its details, its checklist, its comments
+// and its history, which records where it went. The move is undone on each board on
+// its own: undoing it on this board puts the task back without taking it off the
+// other one. It returns the task as it was added to the other board.
+func (self *UserConfig) MoveTaskToBoard(board *Board, task *Task, target *Board) (*Task, error) {
+	return self.transferTask(board, task, target, false)
+}
+
+// CopyTaskToBoard adds a copy of the task on top of the backlog column of the other
+// board, with the same details and checklist. The history of the task records where
+// it was copied to. It returns the copy.
+func (self *UserConfig) CopyTaskToBoard(board *Board, task *Task, target *Board) (*Task, error) {
+	return self.transferTask(board, task, target, true)
+}
+
+func (self *UserConfig) transferTask(board *Board, task *Task, target *Board, isCopy bool) (*Task, error) {
+	if target.Id == board.Id {
+		return nil, errors.New("The task is already on this board")
+	}
+
+	if len(target.Columns) == 0 {
+		return nil, fmt.Errorf("The board \"%s\" has no columns to add the task to", target.Name)
+	}
+
+	column, _ := board.GetColumnForTask(task)
+	if column == nil {
+		return nil, errors.New("Couldn't find the column of the task")
+	}
+
+	key := board.GetTaskKey(task)
+	ret := task.newTransferredTask(isCopy)
+
+	// The task is added to the other board first, so the history of the one left
+	// behind can say what its key there is.
+	err := self.runCommand(target, fmt.Sprintf("Add \"%s\" from %s", ret.Title, board.Name), []string{ret.Id}, func() error {
+		target.assignTaskNumber(ret)
+
+		backlogColumn := target.GetBacklogColumn()
+		target.Tasks[backlogColumn.Id] = append(target.Tasks[backlogColumn.Id], ret)
+
+		event := newTaskEvent(TaskMovedFromBoard)
+		if isCopy {
+			event.Kind = TaskCreated
+		}
+		event.Board = board.Name
+		event.TaskKey = key
+		ret.Events = append(ret.Events, event)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := newTaskEvent(TaskMovedToBoard)
+	if isCopy {
+		event.Kind = TaskCopiedToBoard
+	}
+	event.Board = target.Name
+	event.TaskKey = target.GetTaskKey(ret)
+
+	if isCopy {
+		err = self.runCommand(board, fmt.Sprintf("Copy \"%s\" to %s", task.Title, target.Name), []string{task.Id}, func() error {
+			task.Events = append(task.Events, event)
+			return nil
+		})
+
+		return ret, err
+	}
+
+	// The move is added to the history of the task as it's taken off the board, so
+	// it's kept along with the task if the move is undone.
+	err = self.runCommand(board, fmt.Sprintf("Move \"%s\" to %s", task.Title, target.Name), []string{task.Id}, func() error {
+		task.Events = append(task.Events, event)
+		board.removeTaskFromColumn(task, column.Id)
+		return nil
+	})
+
+	return ret, err
+}
+
+// newTransferredTask returns the task as it's added to another board. It gets an ID
+// of its own, and leaves behind what only makes sense on its board, like its blockers
+// and its key. A copy starts a history of its own, without the comments of the task.
+func (task *Task) newTransferredTask(isCopy bool) *Task {
+	ret := task.copy()
+
+	ret.Id = uuid.New().String()
+	ret.Number = 0
+	ret.BlockedBy = nil
+	ret.PreviousOccurrenceId = ""
+	ret.NextOccurrenceId = ""
+
+	if isCopy {
+		ret.CreatedAt = timestampNow()
+		ret.UpdatedAt = ret.CreatedAt
+		ret.StartedAt = nil
+		ret.CompletedAt = nil
+		ret.Comments = nil
+		ret.Events = nil
+
+		for _, it := range ret.Checklist {
+			it.Id = uuid.New().String()
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"testing"
+)
+
+// lastTestEvent returns the last event in the history of the task.
+func lastTestEvent(t *testing.T, task *Task) *TaskEvent {
+	t.Helper()
+
+	if len(task.Events) == 0 {
+		t.Fatalf("Expected \"%s\" to have a history", task.Title)
+	}
+
+	return task.Events[len(task.Events)-1]
+}
+
+// countTestEvents returns how many events of the given kind the history of the task has.
+func countTestEvents(task *Task, kind TaskEventKind) int {
+	ret := 0
+
+	for _, event := range task.Events {
+		if event.Kind == kind {
+			ret++
+		}
+	}
+
+	return ret
+}
+
+func TestTransferTaskToBoard(t *testing.T) {
+	tests := []struct {
+		name   string
+		isCopy bool
+		// check is run once the task was moved or copied from "api" to "web", and
+		// again on the boards as they're read back.
+		check func(t *testing.T, source *Board, target *Board)
+		// checkUndone is run once the move or the copy is undone on "api".
+		checkUndone func(t *testing.T, source *Board, target *Board)
+	}{
+		{
+			name: "moving a task",
+			check: func(t *testing.T, source *Board, target *Board) {
+				if task, _ := findTestTask(source, "one"); task != nil {
+					t.Errorf("Expected the task to be taken off the board it came from")
+				}
+
+				moved, column := mustFindTestTask(t, target, "one")
+				if column.Role != BacklogRole || target.GetTaskKey(moved) != "WEB-1" {
+					t.Errorf("Expected the task to be WEB-1 in the backlog, got %s in %s", target.GetTaskKey(moved), column.Name)
+				}
+				if len(moved.Comments) != 1 {
+					t.Errorf("Expected the task to keep its comments")
+				}
+				if len(moved.BlockedBy) != 0 {
+					t.Errorf("Expected the blockers of the task to be left behind")
+				}
+
+				event := lastTestEvent(t, moved)
+				if event.Kind != TaskMovedFromBoard || event.Board != "api" || event.TaskKey != "API-1" {
+					t.Errorf("Expected the history of the task to say where it came from, got %+v", event)
+				}
+			},
+			checkUndone: func(t *testing.T, source *Board, target *Board) {
+				task, column := mustFindTestTask(t, source, "one")
+				if column.Role != BacklogRole || source.GetTaskKey(task) != "API-1" {
+					t.Errorf("Expected the task to be put back as API-1, got %s in %s", source.GetTaskKey(task), column.Name)
+				}
+
+				// The move is kept in its history, once, and it was never deleted.
+				if countTestEvents(task, TaskMovedToBoard) != 1 || countTestEvents(task, TaskDeleted) != 0 {
+					t.Errorf("Expected the history of the task to have its move and no delete")
+				}
+				if lastTestEvent(t, task).Kind != TaskRestored {
+					t.Errorf("Expected the history of the task to end with it coming back")
+				}
+
+				// The move is undone on each board on its own.
+				mustFindTestTask(t, target, "one")
+			},
+		},
+		{
+			name:   "copying a task",
+			isCopy: true,
+			check: func(t *testing.T, source *Board, target *Board) {
+				task, _ := mustFindTestTask(t, source, "one")
+				event := lastTestEvent(t, task)
+				if event.Kind != TaskCopiedToBoard || event.Board != "web" || event.TaskKey != "WEB-1" {
+					t.Errorf("Expected the history of the task to say where it was copied to, got %+v", event)
+				}
+
+				copied, _ := mustFindTestTask(t, target, "one")
+				if copied.Id == task.Id || target.GetTaskKey(copied) != "WEB-1" {
+					t.Errorf("Expected the copy to be a task of its own")
+				}
+				if len(copied.Comments) != 0 {
+					t.Errorf("Expected the copy not to have the comments of the task")
+				}
+				if len(copied.Events) != 1 || copied.Events[0].Kind != TaskCreated || copied.Events[0].TaskKey != "API-1" {
+					t.Errorf("Expected the copy to start a history of its own, got %+v", copied.Events)
+				}
+			},
+			checkUndone: func(t *testing.T, source *Board, target *Board) {
+				mustFindTestTask(t, source, "one")
+				mustFindTestTask(t, target, "one")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, source := newTestConfig(t)
+			target, err := config.CreateBoard("web", "/projects/web")
+			if err != nil {
+				t.Fatalf("Failed to create the board. %s", err)
+			}
+
+			task := addTestTask(t, config, source, "one")
+			blocker := addTestTask(t, config, source, "two")
+			mustSucceed(t, config.AddBlocker(source.Id, task, blocker))
+			_, err = config.AddComment(source.Id, task, "omar", "Started on it")
+			mustSucceed(t, err)
+
+			if test.isCopy {
+				_, err = config.CopyTaskToBoard(source, task, target)
+			} else {
+				_, err = config.MoveTaskToBoard(source, task, target)
+			}
+			mustSucceed(t, err)
+
+			test.check(t, source, target)
+
+			reopened := reopenConfig(t, config)
+			test.check(t, openTestBoard(t, reopened, source.Id), openTestBoard(t, reopened, target.Id))
+
+			undone, err := config.Undo(source)
+			mustSucceed(t, err)
+			if undone.IsNone() {
+				t.Fatalf("Expected the move or the copy to be undone")
+			}
+			test.checkUndone(t, source, target)
+
+			// Undoing it on the other board takes the task off that one.
+			undone, err = config.Undo(target)
+			mustSucceed(t, err)
+			if undone.IsNone() {
+				t.Fatalf("Expected adding the task to the other board to be undone")
+			}
+			if task, _ := findTestTask(target, "one"); task != nil {
+				t.Errorf("Expected the task to be taken off the other board")
+			}
+
+			// Redoing the move on the board it came from takes it off again.
+			redone, err := config.Redo(source)
+			mustSucceed(t, err)
+			if redone.IsNone() {
+				t.Fatalf("Expected the move or the copy to be redone")
+			}
+			if !test.isCopy {
+				if task, _ := findTestTask(source, "one"); task != nil {
+					t.Errorf("Expected the task to be taken off the board again")
+				}
+			}
+		})
+	}
+}
+
+func TestMoveTaskToBoardThatFailsToBeSaved(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore()}
+	config, err := SetupUserConfigInStore(store)
+	if err != nil {
+		t.Fatalf("Failed to set up the config. %s", err)
+	}
+	source, err := config.CreateBoard("api", "/projects/api")
+	if err != nil {
+		t.Fatalf("Failed to create the board. %s", err)
+	}
+	target, err := config.CreateBoard("web", "/projects/web")
+	if err != nil {
+		t.Fatalf("Failed to create the board. %s", err)
+	}
+
+	task := addTestTask(t, config, source, "one")
+	events := len(task.Events)
+
+	// The task is added to the other board, but taking it off this one fails.
+	store.failBoardId = source.Id
+	_, err = config.MoveTaskToBoard(source, task, target)
+	if err == nil {
+		t.Fatalf("Expected the move to fail to be saved")
+	}
+
+	// The move is part of the change that's rolled back, history and all.
+	if _, column := mustFindTestTask(t, source, "one"); column.Role != BacklogRole {
+		t.Errorf("Expected the task to stay in the backlog, got %s", column.Name)
+	}
+	if len(task.Events) != events || countTestEvents(task, TaskMovedToBoard) != 0 {
+		t.Errorf("Expected the history of the task to stay as it was, got %+v", task.Events)
+	}
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/okira-e/gotasks/internal/opt"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// ChecklistItem is a single step in the checklist of a task.
+type ChecklistItem struct {
+	Id   string `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+func newChecklistItem(text string) *ChecklistItem {
+	ret := new(ChecklistItem)
+
+	ret.Id = uuid.New().String()
+	ret.Text = text
+
+	return ret
+}
+
+// GetChecklistProgress returns how many items of the checklist of the task are done
+// out of how many there are.
+func (task *Task) GetChecklistProgress() (done int, total int) {
+	for _, it := range task.Checklist {
+		if it.Done {
+			done++
+		}
+	}
+
+	return done, len(task.Checklist)
+}
+
+// GetChecklistItemById returns the item of the checklist with the given ID.
+func (task *Task) GetChecklistItemById(itemId string) opt.Option[*ChecklistItem] {
+	for _, it := range task.Checklist {
+		if it.Id == itemId {
+			return opt.Some(it)
+		}
+	}
+
+	return opt.None[*ChecklistItem]()
+}
+
+func (task *Task) removeChecklistItem(itemId string) {
+	for i, it := range task.Checklist {
+		if it.Id == itemId {
+			task.Checklist = append(task.Checklist[:i:i], task.Checklist[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddChecklistItem adds an item with the given text to the end of the checklist of the task.
+func (self *UserConfig) AddChecklistItem(boardId string, task *Task, text string) (*ChecklistItem, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("A checklist item can't be empty")
+	}
+
+	board, err := self.getBoardForChecklist(boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	item := newChecklistItem(text)
+
+	err = self.runCommand(board, "Add \"" + text + "\" to \"" + task.Title + "\"", []string{task.Id}, func() error {
+		task.Checklist = append(task.Checklist, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ToggleChecklistItem checks the item of the checklist of the task, or unchecks it
+// if it was checked.
+func (self *UserConfig) ToggleChecklistItem(boardId string, task *Task, itemId string) error {
+	board, err := self.getBoardForChecklist(boardId)
+	if err != nil {
+		return err
+	}
+
+	itemOpt := task.GetChecklistItemById(itemId)
+	if itemOpt.IsNone() {
+		return fmt.Errorf("Couldn't find the checklist item in \"%s\"", task.Title)
+	}
+
+	item := itemOpt.Unwrap()
+
+	return self.runCommand(board, utils.Cond(item.Done, "Uncheck", "Check") + " \"" + item.Text + "\" in \"" + task.Title + "\"", []string{task.Id}, func() error {
+		item.Done = !item.Done
+		return nil
+	})
+}
+
+// RemoveChecklistItem removes the item from the checklist of the task.
+func (self *UserConfig) RemoveChecklistItem(boardId string, task *Task, itemId string) error {
+	board, err := self.getBoardForChecklist(boardId)
+	if err != nil {
+		return err
+	}
+
+	itemOpt := task.GetChecklistItemById(itemId)
+	if itemOpt.IsNone() {
+		return fmt.Errorf("Couldn't find the checklist item in \"%s\"", task.Title)
+	}
+
+	item := itemOpt.Unwrap()
+
+	return self.runCommand(board, "Remove \"" + item.Text + "\" from \"" + task.Title + "\"", []string{task.Id}, func() error {
+		task.removeChecklistItem(itemId)
+		return nil
+	})
+}
+
+// PromoteChecklistItem turns the item of the checklist of the task into a task of its
+// own on the same board, taking it off the checklist. The new task is added like any
+// other new task, and undoing puts the item back.
+func (self *UserConfig) PromoteChecklistItem(boardId string, task *Task, itemId string) (*Task, error) {
+	utils.SaveLog(utils.Debug, "Promoting a checklist item", map[string]any{"task": task, "itemId": itemId})
+
+	board, err := self.getBoardForChecklist(boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(board.Columns) == 0 {
+		return nil, errors.New("No columns found to add this task to.")
+	}
+
+	itemOpt := task.GetChecklistItemById(itemId)
+	if itemOpt.IsNone() {
+		return nil, fmt.Errorf("Couldn't find the checklist item in \"%s\"", task.Title)
+	}
+
+	item := itemOpt.Unwrap()
+	promoted := NewTask(item.Text, "")
+	columnId := board.GetBacklogColumn().Id
+
+	err = self.runCommand(board, "Promote \"" + item.Text + "\" to a task", []string{task.Id, promoted.Id}, func() error {
+		task.removeChecklistItem(itemId)
+		board.Tasks[columnId] = append(board.Tasks[columnId], promoted)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+func (self *UserConfig) getBoardForChecklist(boardId string) (*Board, error) {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return nil, errors.New("Couldn't find the board while trying to change a checklist")
+	}
+
+	return boardOpt.Unwrap(), nil
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ColumnRole is what being in a column means for a task, like being done with it.
+// It's what the board goes by rather than where the column is, so a board can end
+// with a "Won't do" column after its "Done" one.
+type ColumnRole string
+
+const (
+	// BacklogRole is for tasks that weren't started yet. New tasks go to the first
+	// backlog column.
+	BacklogRole ColumnRole = "backlog"
+	// ActiveRole is for tasks being worked on.
+	ActiveRole ColumnRole = "active"
+	// DoneRole is for finished tasks, which count towards the progress of the board.
+	DoneRole ColumnRole = "done"
+	// CancelledRole is for tasks that won't be done. They're closed like done tasks,
+	// but they don't count towards the progress of the board.
+	CancelledRole ColumnRole = "cancelled"
+)
+
+// ColumnRoles are all the roles a column can have, in the order they're cycled through.
+var ColumnRoles = []ColumnRole{BacklogRole, ActiveRole, DoneRole, CancelledRole}
+
+// ParseColumnRole reads a role like "done". The case doesn't matter.
+func ParseColumnRole(text string) (ColumnRole, error) {
+	for _, it := range ColumnRoles {
+		if strings.EqualFold(string(it), strings.TrimSpace(text)) {
+			return it, nil
+		}
+	}
+
+	roles := []string{}
+	for _, it := range ColumnRoles {
+		roles = append(roles, string(it))
+	}
+
+	return "", fmt.Errorf("\"%s\" isn't a role a column can have, pick one of %s", text, strings.Join(roles, ", "))
+}
+
+// IsClosed reports if tasks in the column are done or cancelled.
+func (column *Column) IsClosed() bool {
+	return column.Role == DoneRole || column.Role == CancelledRole
+}
+
+// GetBacklogColumn returns the column new tasks are added to, which is the first
+// backlog column, or the first column if none is. It's nil if the board has no columns.
+func (board *Board) GetBacklogColumn() *Column {
+	for _, it := range board.Columns {
+		if it.Role == BacklogRole {
+			return it
+		}
+	}
+
+	if len(board.Columns) == 0 {
+		return nil
+	}
+
+	return board.Columns[0]
+}
+
+// getTaskRole returns the role of the column the task is in, or an empty role if
+// the task isn't on the board.
+func (board *Board) getTaskRole(task *Task) ColumnRole {
+	column, _ := board.GetColumnForTask(task)
+	if column == nil {
+		return ""
+	}
+
+	return column.Role
+}
+
+// IsDone reports if the task is in a done column.
+func (board *Board) IsDone(task *Task) bool {
+	return board.getTaskRole(task) == DoneRole
+}
+
+// IsClosed reports if the task is in a done or a cancelled column, meaning there's
+// nothing left to do about it.
+func (board *Board) IsClosed(task *Task) bool {
+	role := board.getTaskRole(task)
+
+	return role == DoneRole || role == CancelledRole
+}
+
+// GetProgress returns how many tasks on the board are done, out of the ones that
+// count. Cancelled tasks don't count.
+func (board *Board) GetProgress() (int, int) {
+	done := 0
+	total := 0
+
+	for _, column := range board.Columns {
+		switch column.Role {
+		case CancelledRole:
+			continue
+		case DoneRole:
+			done += len(board.Tasks[column.Id])
+		}
+
+		total += len(board.Tasks[column.Id])
+	}
+
+	return done, total
+}
+
+// SetColumnRole sets the role of the column with the given ID.
+// Changes to the columns are saved right away, they can't be undone.
+func (self *UserConfig) SetColumnRole(boardId string, columnId string, role ColumnRole) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to set the role of a column")
+	}
+
+	board := boardOpt.Unwrap()
+
+	columnOpt := board.GetColumnById(columnId)
+	if columnOpt.IsNone() {
+		return errors.New("Couldn't find the column to set the role of")
+	}
+
+	columnOpt.Unwrap().Role = role
+
+	return self.UpdateBoard(board)
+}
+
+// migrateColumnRoles gives every column of the board a role going by where it is,
+// which is what the board went by before: the first column is the backlog, the last
+// one is done and the ones in between are active. A board with a single column is a
+// plain list that tasks are added to, so its column is the backlog. Making it done
+// would have every task on it count as completed.
+func migrateColumnRoles(board document) error {
+	columns, _ := board["columns"].([]any)
+
+	if len(columns) == 1 {
+		if column, ok := columns[0].(document); ok {
+			column["role"] = string(BacklogRole)
+		}
+
+		return nil
+	}
+
+	for i, it := range columns {
+		column, ok := it.(document)
+		if !ok {
+			continue
+		}
+
+		role := ActiveRole
+		if i == 0 {
+			role = BacklogRole
+		} else if i == len(columns) - 1 {
+			role = DoneRole
+		}
+
+		column["role"] = string(role)
+	}
+
+	return nil
+}
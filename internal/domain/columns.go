@@ -0,0 +1,397 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gizak/termui/v3"
+	"github.com/google/uuid"
+	"github.com/okira-e/gotasks/internal/opt"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// Column is a column of a board. Tasks are kept under the ID of their column, so a
+// column can be renamed or moved around without losing them.
+type Column struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// WipLimit is the most tasks the column should have at once. 0 means no limit.
+	WipLimit int `json:"wip_limit,omitempty"`
+	// Color is the name of the color of the column's header, like "green". The header
+	// is in the primary color if it's empty. See ColumnColors.
+	Color string `json:"color,omitempty"`
+	// Role is what being in the column means for a task, like being done with it.
+	Role ColumnRole `json:"role"`
+}
+
+func newColumn(name string, role ColumnRole) *Column {
+	ret := new(Column)
+
+	ret.Id = uuid.New().String()
+	ret.Name = name
+	ret.Role = role
+
+	return ret
+}
+
+// defaultColumns returns the columns a new board starts with.
+func defaultColumns() []*Column {
+	return []*Column{
+		newColumn("Todo", BacklogRole),
+		newColumn("In Progress", ActiveRole),
+		newColumn("Done", DoneRole),
+	}
+}
+
+// ColumnColors are the colors a column can be given, in the order they're cycled through.
+var ColumnColors = []string{"red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+var columnColorValues = map[string]termui.Color{
+	"red":     termui.ColorRed,
+	"green":   termui.ColorGreen,
+	"yellow":  termui.ColorYellow,
+	"blue":    termui.ColorBlue,
+	"magenta": termui.ColorMagenta,
+	"cyan":    termui.ColorCyan,
+	"white":   termui.ColorWhite,
+}
+
+// GetColor returns the color of the column, or the given one if it has none.
+func (column *Column) GetColor(fallback termui.Color) termui.Color {
+	if color, ok := columnColorValues[column.Color]; ok {
+		return color
+	}
+
+	return fallback
+}
+
+// GetColumnById returns the column of the board with the given ID.
+func (board *Board) GetColumnById(columnId string) opt.Option[*Column] {
+	for _, it := range board.Columns {
+		if it.Id == columnId {
+			return opt.Some(it)
+		}
+	}
+
+	return opt.None[*Column]()
+}
+
+// GetColumnName returns the name of the column with the given ID, or an empty string
+// if the board has no such column anymore.
+func (board *Board) GetColumnName(columnId string) string {
+	columnOpt := board.GetColumnById(columnId)
+	if columnOpt.IsNone() {
+		return ""
+	}
+
+	return columnOpt.Unwrap().Name
+}
+
+// FindColumn searches the columns of the board for the one with the given name, which
+// isn't case sensitive, or ID. A prefix of the ID is enough as long as only one column
+// starts with it.
+func (board *Board) FindColumn(nameOrId string) (*Column, error) {
+	var found *Column
+
+	for _, it := range board.Columns {
+		if it.Id == nameOrId || strings.EqualFold(it.Name, nameOrId) {
+			return it, nil
+		}
+
+		if nameOrId == "" || !strings.HasPrefix(it.Id, nameOrId) {
+			continue
+		}
+
+		if found != nil {
+			return nil, fmt.Errorf("More than one column has an ID that starts with \"%s\"", nameOrId)
+		}
+
+		found = it
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("The board has no column named \"%s\"", nameOrId)
+	}
+
+	return found, nil
+}
+
+// GetColumnIndex returns the index of the column with the given ID, or -1 if the board
+// has no such column.
+func (board *Board) GetColumnIndex(columnId string) int {
+	return slices.IndexFunc(board.Columns, func(it *Column) bool { return it.Id == columnId })
+}
+
+// checkColumnName returns an error if no column can be given the name, like when
+// another column already has it. The column with the given ID is the one being named,
+// it's empty for a new column.
+func (board *Board) checkColumnName(columnId string, name string) error {
+	if name == "" {
+		return errors.New("A column needs a name")
+	}
+
+	for _, it := range board.Columns {
+		if it.Id != columnId && strings.EqualFold(it.Name, name) {
+			return fmt.Errorf("The board already has a column named \"%s\"", it.Name)
+		}
+	}
+
+	return nil
+}
+
+// adoptOrphanedTasks moves the tasks kept under a column the board doesn't have, like
+// one that was deleted by another process while tasks were added to it here, to the
+// backlog column.
+func (board *Board) adoptOrphanedTasks() {
+	if len(board.Columns) == 0 {
+		return
+	}
+
+	backlogColumn := board.GetBacklogColumn()
+
+	for columnId, tasks := range board.Tasks {
+		columnOpt := board.GetColumnById(columnId)
+		if columnOpt.IsSome() {
+			continue
+		}
+
+		if len(tasks) > 0 {
+			utils.SaveLog(utils.Warn, "Found tasks in a column the board doesn't have, moving them to the backlog column", map[string]any{"column": columnId, "tasks": len(tasks)})
+		}
+
+		board.Tasks[backlogColumn.Id] = append(board.Tasks[backlogColumn.Id], tasks...)
+		delete(board.Tasks, columnId)
+	}
+}
+
+// AddColumn adds a column with the given name and role to the board at the given
+// position, or at the end if the position is out of range.
+// Changes to the columns are saved right away, they can't be undone.
+func (self *UserConfig) AddColumn(boardId string, name string, role ColumnRole, position int) (*Column, error) {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return nil, errors.New("Couldn't find the board while trying to add a column")
+	}
+
+	board := boardOpt.Unwrap()
+
+	name = strings.TrimSpace(name)
+	err := board.checkColumnName("", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if position < 0 || position > len(board.Columns) {
+		position = len(board.Columns)
+	}
+
+	column := newColumn(name, role)
+	board.Columns = slices.Insert(board.Columns, position, column)
+
+	err = self.UpdateBoard(board)
+	if err != nil {
+		return nil, err
+	}
+
+	return column, nil
+}
+
+// RenameColumn gives the column with the given ID a new name. Its tasks stay in it.
+func (self *UserConfig) RenameColumn(boardId string, columnId string, name string) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to rename a column")
+	}
+
+	board := boardOpt.Unwrap()
+
+	columnOpt := board.GetColumnById(columnId)
+	if columnOpt.IsNone() {
+		return errors.New("Couldn't find the column to rename")
+	}
+
+	name = strings.TrimSpace(name)
+	err := board.checkColumnName(columnId, name)
+	if err != nil {
+		return err
+	}
+
+	columnOpt.Unwrap().Name = name
+
+	return self.UpdateBoard(board)
+}
+
+// MoveColumn moves the column with the given ID to the given position among the
+// columns, counting from 0. Positions out of range move it to the start or the end.
+func (self *UserConfig) MoveColumn(boardId string, columnId string, position int) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to move a column")
+	}
+
+	board := boardOpt.Unwrap()
+
+	i := board.GetColumnIndex(columnId)
+	if i == -1 {
+		return errors.New("Couldn't find the column to move")
+	}
+
+	position = min(max(position, 0), len(board.Columns) - 1)
+	if position == i {
+		return nil
+	}
+
+	column := board.Columns[i]
+	columns := slices.Delete(slices.Clone(board.Columns), i, i+1)
+	board.Columns = slices.Insert(columns, position, column)
+
+	return self.UpdateBoard(board)
+}
+
+// DeleteColumn removes the column with the given ID from the board. Its tasks are
+// moved on top of the column with the other ID, which is only needed if it has any.
+// A board can't be left without columns. The tasks are moved the same way moving
+// them by hand does, and moving them can be undone, while the column stays deleted.
+func (self *UserConfig) DeleteColumn(boardId string, columnId string, moveToColumnId string) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to delete a column")
+	}
+
+	board := boardOpt.Unwrap()
+
+	i := board.GetColumnIndex(columnId)
+	if i == -1 {
+		return errors.New("Couldn't find the column to delete")
+	}
+
+	column := board.Columns[i]
+
+	if len(board.Columns) == 1 {
+		return errors.New("The last column of a board can't be deleted")
+	}
+
+	deleteColumn := func() {
+		board.Columns = slices.Delete(slices.Clone(board.Columns), i, i+1)
+		delete(board.Tasks, columnId)
+	}
+
+	tasks := slices.Clone(board.Tasks[columnId])
+	if len(tasks) == 0 {
+		deleteColumn()
+		return self.UpdateBoard(board)
+	}
+
+	if moveToColumnId == columnId {
+		return errors.New("The tasks of a column can't be moved to the column itself")
+	}
+
+	moveToOpt := board.GetColumnById(moveToColumnId)
+	if moveToOpt.IsNone() {
+		return fmt.Errorf("\"%s\" has %d tasks, choose a column to move them to", column.Name, len(tasks))
+	}
+
+	moveTo := moveToOpt.Unwrap()
+
+	err := board.checkWipLimit(moveTo, len(tasks))
+	if err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("Move the tasks of \"%s\" to %s", column.Name, moveTo.Name)
+
+	return self.moveTasksToColumn(board, description, tasks, column, moveTo, PlaceOnTop, deleteColumn)
+}
+
+// SetColumnColor sets the color of the header of the column with the given ID. An
+// empty color goes back to the primary color.
+func (self *UserConfig) SetColumnColor(boardId string, columnId string, color string) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to set the color of a column")
+	}
+
+	board := boardOpt.Unwrap()
+
+	columnOpt := board.GetColumnById(columnId)
+	if columnOpt.IsNone() {
+		return errors.New("Couldn't find the column to set the color of")
+	}
+
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color != "" && !slices.Contains(ColumnColors, color) {
+		return fmt.Errorf("\"%s\" isn't a color a column can have, pick one of %s", color, strings.Join(ColumnColors, ", "))
+	}
+
+	columnOpt.Unwrap().Color = color
+
+	return self.UpdateBoard(board)
+}
+
+// migrateColumnsToObjects turns the columns of the board from names into objects with
+// an ID, keys the tasks by the IDs of their columns rather than their names, and moves
+// the WIP limits of the board into its columns. The archive and the trash remember the
+// columns their tasks were taken from by ID too.
+func migrateColumnsToObjects(board document) error {
+	names, _ := board["columns"].([]any)
+	wipLimits, _ := board["wip_limits"].(document)
+	tasks, _ := board["tasks"].(document)
+
+	columns := []any{}
+	columnIds := map[string]string{}
+	migratedTasks := document{}
+
+	for _, it := range names {
+		name, ok := it.(string)
+		if !ok {
+			continue
+		}
+
+		// Names were unique since they keyed the tasks, but a config edited by hand
+		// could still repeat one. The tasks go to the first column with the name.
+		if _, ok := columnIds[name]; ok {
+			continue
+		}
+
+		columnId := uuid.New().String()
+		columnIds[name] = columnId
+
+		column := document{
+			"id":   columnId,
+			"name": name,
+		}
+		if limit, ok := wipLimits[name].(float64); ok && limit > 0 {
+			column["wip_limit"] = limit
+		}
+
+		columns = append(columns, column)
+		migratedTasks[columnId] = tasks[name]
+	}
+
+	// Tasks under a name that isn't one of the columns were never shown. They're kept
+	// under the name, and the board moves them to its first column when it's read.
+	for name, it := range tasks {
+		if _, ok := columnIds[name]; !ok {
+			migratedTasks[name] = it
+		}
+	}
+
+	for _, shelf := range []string{"archive", "trash"} {
+		removedTasks, _ := board[shelf].([]any)
+		for _, it := range removedTasks {
+			removed, _ := it.(document)
+			name, _ := removed["column"].(string)
+			if columnId, ok := columnIds[name]; ok {
+				removed["column"] = columnId
+			}
+		}
+	}
+
+	board["columns"] = columns
+	board["tasks"] = migratedTasks
+	delete(board, "wip_limits")
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeleteColumnMovesItsTasks(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup changes the board before "In Progress" is deleted into "Done".
+		setup func(t *testing.T, config *UserConfig, board *Board)
+		check func(t *testing.T, config *UserConfig, board *Board, err error)
+	}{
+		{
+			name:  "the tasks are moved like moving them by hand",
+			setup: func(t *testing.T, config *UserConfig, board *Board) {},
+			check: func(t *testing.T, config *UserConfig, board *Board, err error) {
+				if err != nil {
+					t.Fatalf("Failed to delete the column. %s", err)
+				}
+				if len(board.Columns) != 2 {
+					t.Fatalf("Expected the column to be deleted, got %d columns", len(board.Columns))
+				}
+
+				for _, title := range []string{"one", "two"} {
+					task, column := mustFindTestTask(t, board, title)
+					if column.Name != "Done" {
+						t.Errorf("Expected %s to be moved to Done, got %s", title, column.Name)
+					}
+					if task.CompletedAt == nil {
+						t.Errorf("Expected %s to be completed once it's in a done column", title)
+					}
+
+					event := task.Events[len(task.Events)-1]
+					if event.Kind != TaskMoved || event.FromColumn != "In Progress" || event.ToColumn != "Done" {
+						t.Errorf("Expected the history of %s to have the move, got %+v", title, event)
+					}
+				}
+
+				// They keep their order, on top of the column.
+				tasks := board.Tasks[board.Columns[1].Id]
+				if len(tasks) != 2 || tasks[0].Title != "one" || tasks[1].Title != "two" {
+					t.Errorf("Expected the tasks to keep their order")
+				}
+			},
+		},
+		{
+			name: "a recurring task comes back",
+			setup: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				recurrence, _ := ParseRecurrence("weekly")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Recurrence = recurrence }))
+			},
+			check: func(t *testing.T, config *UserConfig, board *Board, err error) {
+				if err != nil {
+					t.Fatalf("Failed to delete the column. %s", err)
+				}
+
+				// The next occurrence goes by the same title, so the task is the one that's done.
+				task := board.Tasks[board.Columns[1].Id][0]
+				nextOpt := board.GetTaskById(task.NextOccurrenceId)
+				if task.Title != "one" || nextOpt.IsNone() {
+					t.Fatalf("Expected the next occurrence of the task to be added")
+				}
+				if column, _ := board.GetColumnForTask(nextOpt.Unwrap()); column.Role != BacklogRole {
+					t.Errorf("Expected the next occurrence to be in the backlog, got %s", column.Name)
+				}
+			},
+		},
+		{
+			name: "a strict WIP limit is kept",
+			setup: func(t *testing.T, config *UserConfig, board *Board) {
+				mustSucceed(t, config.SetWipLimit(board.Id, board.Columns[2].Id, 1))
+				mustSucceed(t, config.SetStrictWipLimits(board.Id, true))
+			},
+			check: func(t *testing.T, config *UserConfig, board *Board, err error) {
+				var wipLimitError *WipLimitError
+				if !errors.As(err, &wipLimitError) {
+					t.Fatalf("Expected a WipLimitError, got %v", err)
+				}
+				if len(board.Columns) != 3 {
+					t.Errorf("Expected the column to be kept, got %d columns", len(board.Columns))
+				}
+				if _, column := mustFindTestTask(t, board, "one"); column.Name != "In Progress" {
+					t.Errorf("Expected the tasks to stay where they were, got %s", column.Name)
+				}
+			},
+		},
+		{
+			name:  "moving the tasks can be undone",
+			setup: func(t *testing.T, config *UserConfig, board *Board) {},
+			check: func(t *testing.T, config *UserConfig, board *Board, err error) {
+				if err != nil {
+					t.Fatalf("Failed to delete the column. %s", err)
+				}
+
+				_, err = config.Undo(board)
+				if err != nil {
+					t.Fatalf("Failed to undo. %s", err)
+				}
+
+				if len(board.Columns) != 2 {
+					t.Errorf("Expected the column to stay deleted, got %d columns", len(board.Columns))
+				}
+				for _, title := range []string{"one", "two"} {
+					task, column := mustFindTestTask(t, board, title)
+					if column.Role != BacklogRole {
+						t.Errorf("Expected %s to be put back in the backlog, since its column is gone, got %s", title, column.Name)
+					}
+					if task.CompletedAt != nil {
+						t.Errorf("Expected %s not to be completed anymore", title)
+					}
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, board := newTestConfig(t)
+			for _, title := range []string{"one", "two"} {
+				task := addTestTask(t, config, board, title)
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+			}
+
+			test.setup(t, config, board)
+
+			err := config.DeleteColumn(board.Id, board.Columns[1].Id, board.Columns[2].Id)
+
+			test.check(t, config, board, err)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// TaskComment is a note left on a task, like a status update. Comments are only
+// ever added to a task, never changed or removed.
+type TaskComment struct {
+	Id     string `json:"id"`
+	Author string `json:"author"`
+	Text   string    `json:"text"`
+	At     time.Time `json:"at"`
+}
+
+func newTaskComment(author string, text string) *TaskComment {
+	ret := new(TaskComment)
+
+	ret.Id = uuid.New().String()
+	ret.Author = author
+	ret.Text = text
+	ret.At = timestampNow()
+
+	return ret
+}
+
+// GetDefaultCommentAuthor returns who comments are left by when no one is given.
+// It's the user.name of git for the current directory, or the name of the user
+// logged in if git has none.
+func GetDefaultCommentAuthor() string {
+	output, err := exec.Command("git", "config", "user.name").Output()
+	if err == nil {
+		if name := strings.TrimSpace(string(output)); name != "" {
+			return name
+		}
+	}
+
+	for _, it := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(it); name != "" {
+			return name
+		}
+	}
+
+	return "unknown"
+}
+
+// AddComment adds a comment with the given text to the end of the comments of the task.
+func (self *UserConfig) AddComment(boardId string, task *Task, author string, text string) (*TaskComment, error) {
+	utils.SaveLog(utils.Debug, "Adding a comment", map[string]any{"task": task})
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("A comment can't be empty")
+	}
+
+	author = strings.TrimSpace(author)
+	if author == "" {
+		author = GetDefaultCommentAuthor()
+	}
+
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return nil, errors.New("Couldn't find the board while trying to add a comment")
+	}
+
+	board := boardOpt.Unwrap()
+	comment := newTaskComment(author, text)
+
+	err := self.runCommand(board, "Comment on \"" + task.Title + "\"", []string{task.Id}, func() error {
+		task.Comments = append(task.Comments, comment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// mergeTaskComments merges the comments of a task that two processes added to. Since
+// comments are only added, every comment on either side is kept, oldest first.
+func mergeTaskComments(ours []*TaskComment, theirs []*TaskComment) []*TaskComment {
+	ret := slices.Clone(ours)
+
+	for _, it := range theirs {
+		if !slices.ContainsFunc(ret, func(comment *TaskComment) bool { return comment.Id == it.Id }) {
+			ret = append(ret, it)
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].At.Before(ret[j].At)
+	})
+
+	return ret
+}
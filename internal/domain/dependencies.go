@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// FindTaskOnBoard searches the columns of the board for the task with the given key
+// or ID. A prefix of the ID is enough as long as only one task on the board starts with it.
+func (board *Board) FindTaskOnBoard(taskId string) (*Task, error) {
+	var found *Task
+
+	for _, column := range board.Columns {
+		for _, task := range board.Tasks[column.Id] {
+			if task.Id == taskId || board.matchesTaskKey(task, taskId) {
+				return task, nil
+			}
+
+			if taskId == "" || !strings.HasPrefix(task.Id, taskId) {
+				continue
+			}
+
+			if found != nil {
+				return nil, fmt.Errorf("More than one task has an ID that starts with \"%s\"", taskId)
+			}
+
+			found = task
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("Couldn't find a task with the key or ID \"%s\" on the board", taskId)
+	}
+
+	return found, nil
+}
+
+// GetBlockers returns the tasks on the board that the task is blocked by. Blockers
+// that were taken off the board, like into the trash, are left out.
+func (board *Board) GetBlockers(task *Task) []*Task {
+	ret := []*Task{}
+
+	for _, blockerId := range task.BlockedBy {
+		blockerOpt := board.GetTaskById(blockerId)
+		if blockerOpt.IsSome() {
+			ret = append(ret, blockerOpt.Unwrap())
+		}
+	}
+
+	return ret
+}
+
+// GetOpenBlockers returns the blockers of the task that aren't done or cancelled yet.
+func (board *Board) GetOpenBlockers(task *Task) []*Task {
+	ret := []*Task{}
+
+	for _, blocker := range board.GetBlockers(task) {
+		if !board.IsClosed(blocker) {
+			ret = append(ret, blocker)
+		}
+	}
+
+	return ret
+}
+
+// IsBlocked reports if any of the blockers of the task isn't done or cancelled yet.
+func (board *Board) IsBlocked(task *Task) bool {
+	return len(board.GetOpenBlockers(task)) > 0
+}
+
+// GetDependents returns the tasks on the board that are blocked by the task.
+func (board *Board) GetDependents(task *Task) []*Task {
+	ret := []*Task{}
+
+	for _, column := range board.Columns {
+		for _, it := range board.Tasks[column.Id] {
+			if slices.Contains(it.BlockedBy, task.Id) {
+				ret = append(ret, it)
+			}
+		}
+	}
+
+	return ret
+}
+
+// dependsOn reports if the task with the first ID is blocked by the task with the
+// second one, either directly or through the tasks that block it.
+func (board *Board) dependsOn(taskId string, blockerId string) bool {
+	visited := map[string]bool{}
+	queue := []string{taskId}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		task := board.findTask(current)
+		if task == nil {
+			continue
+		}
+
+		for _, it := range task.BlockedBy {
+			if it == blockerId {
+				return true
+			}
+
+			queue = append(queue, it)
+		}
+	}
+
+	return false
+}
+
+// checkBlocker returns an error if the task with the given ID can't be blocked by
+// the blocker, like when the blocker is already blocked by it.
+func (board *Board) checkBlocker(taskId string, blocker *Task) error {
+	if blocker.Id == taskId {
+		return errors.New("A task can't be blocked by itself")
+	}
+
+	if board.dependsOn(blocker.Id, taskId) {
+		return fmt.Errorf("\"%s\" is already blocked by this task, blocking it back would make a cycle", blocker.Title)
+	}
+
+	return nil
+}
+
+// ParseBlockers reads the comma separated keys, or IDs, of the tasks on the board
+// that the task with the given ID should be blocked by. The ID is empty for a task
+// that isn't on the board yet. Blockers that would make a cycle are rejected.
+func (board *Board) ParseBlockers(taskId string, text string) ([]string, error) {
+	ret := []string{}
+
+	for _, it := range strings.Split(text, ",") {
+		it = strings.TrimSpace(it)
+		if it == "" {
+			continue
+		}
+
+		blocker, err := board.FindTaskOnBoard(it)
+		if err != nil {
+			return nil, err
+		}
+
+		err = board.checkBlocker(taskId, blocker)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(ret, blocker.Id) {
+			ret = append(ret, blocker.Id)
+		}
+	}
+
+	return ret, nil
+}
+
+// AddBlocker marks the task as blocked by the other task on the same board.
+func (self *UserConfig) AddBlocker(boardId string, task *Task, blocker *Task) error {
+	utils.SaveLog(utils.Debug, "Adding a blocker", map[string]any{"task": task, "blocker": blocker})
+
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to add a blocker")
+	}
+
+	board := boardOpt.Unwrap()
+
+	if slices.Contains(task.BlockedBy, blocker.Id) {
+		return nil
+	}
+
+	err := board.checkBlocker(task.Id, blocker)
+	if err != nil {
+		return err
+	}
+
+	return self.runCommand(board, "Block \"" + task.Title + "\" by \"" + blocker.Title + "\"", []string{task.Id}, func() error {
+		task.BlockedBy = append(task.BlockedBy, blocker.Id)
+		return nil
+	})
+}
+
+// RemoveBlocker marks the task as no longer blocked by the task with the given ID.
+func (self *UserConfig) RemoveBlocker(boardId string, task *Task, blockerId string) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to remove a blocker")
+	}
+
+	board := boardOpt.Unwrap()
+
+	i := slices.Index(task.BlockedBy, blockerId)
+	if i == -1 {
+		return fmt.Errorf("\"%s\" isn't blocked by that task", task.Title)
+	}
+
+	return self.runCommand(board, "Unblock \"" + task.Title + "\"", []string{task.Id}, func() error {
+		task.BlockedBy = slices.Delete(slices.Clone(task.BlockedBy), i, i+1)
+		return nil
+	})
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBlockersThatWouldMakeACycle(t *testing.T) {
+	tests := []struct {
+		name string
+		// blocks are the blockers the tasks already have, as pairs of the title of a
+		// task and the title of its blocker.
+		blocks [][2]string
+		// task is blocked by blocker, which fails if wantsError is set.
+		task       string
+		blocker    string
+		wantsError bool
+	}{
+		{
+			name:       "a task blocking itself",
+			task:       "a",
+			blocker:    "a",
+			wantsError: true,
+		},
+		{
+			name:       "a direct cycle",
+			blocks:     [][2]string{{"a", "b"}},
+			task:       "b",
+			blocker:    "a",
+			wantsError: true,
+		},
+		{
+			name:       "an indirect cycle",
+			blocks:     [][2]string{{"a", "b"}, {"b", "c"}},
+			task:       "c",
+			blocker:    "a",
+			wantsError: true,
+		},
+		{
+			name:    "a chain without a cycle",
+			blocks:  [][2]string{{"a", "b"}, {"b", "c"}},
+			task:    "c",
+			blocker: "d",
+		},
+		{
+			name:    "a task blocked through two paths",
+			blocks:  [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}},
+			task:    "a",
+			blocker: "d",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, board := newTestConfig(t)
+			for _, title := range []string{"a", "b", "c", "d"} {
+				addTestTask(t, config, board, title)
+			}
+
+			for _, block := range test.blocks {
+				task, _ := mustFindTestTask(t, board, block[0])
+				blocker, _ := mustFindTestTask(t, board, block[1])
+				mustSucceed(t, config.AddBlocker(board.Id, task, blocker))
+			}
+
+			task, _ := mustFindTestTask(t, board, test.task)
+			blocker, _ := mustFindTestTask(t, board, test.blocker)
+			blockedBy := slices.Clone(task.BlockedBy)
+
+			// Blockers typed in, like in the details of a task, are checked the same way.
+			_, parseErr := board.ParseBlockers(task.Id, board.GetTaskKey(blocker))
+			if (parseErr != nil) != test.wantsError {
+				t.Errorf("Expected parsing the blocker to fail to be %v, got %v", test.wantsError, parseErr)
+			}
+
+			err := config.AddBlocker(board.Id, task, blocker)
+			if (err != nil) != test.wantsError {
+				t.Fatalf("Expected adding the blocker to fail to be %v, got %v", test.wantsError, err)
+			}
+
+			if test.wantsError {
+				if !slices.Equal(task.BlockedBy, blockedBy) {
+					t.Errorf("Expected the blockers of the task to stay as they were, got %v", task.BlockedBy)
+				}
+				return
+			}
+
+			if !slices.Contains(task.BlockedBy, blocker.Id) {
+				t.Errorf("Expected \"%s\" to be blocked by \"%s\"", test.task, test.blocker)
+			}
+			if !board.IsBlocked(task) {
+				t.Errorf("Expected \"%s\" to be blocked while \"%s\" is open", test.task, test.blocker)
+			}
+			if !slices.Contains(board.GetDependents(blocker), task) {
+				t.Errorf("Expected \"%s\" to be a dependent of \"%s\"", test.task, test.blocker)
+			}
+		})
+	}
+}
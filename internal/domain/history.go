@@ -0,0 +1,526 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/okira-e/gotasks/internal/opt"
+)
+
+// historyLimit is how many commands are kept to be undone for every board.
+const historyLimit = 100
+
+// History is the undo and redo stacks of a board. It's persisted apart from the
+// board so undoing still works after gotasks is restarted, without the history
+// being shared along with a board that's committed in a project.
+type History struct {
+	BoardId string `json:"board_id"`
+	// Undo holds the commands that were done, the last one at the end.
+	Undo []*Command `json:"undo"`
+	// Redo holds the commands that were undone, the last one at the end.
+	Redo []*Command `json:"redo"`
+}
+
+// NewHistory returns an empty history for the given board.
+func NewHistory(boardId string) *History {
+	ret := new(History)
+
+	ret.BoardId = boardId
+	ret.Undo = []*Command{}
+	ret.Redo = []*Command{}
+
+	return ret
+}
+
+// Command is a single change made to a board, like moving a task. It records how
+// every task it touched was before and after it, so it can be done and undone any
+// number of times, and so it can be persisted as it is.
+type Command struct {
+	Description string        `json:"description"`
+	At          time.Time     `json:"at"`
+	Changes     []*TaskChange `json:"changes"`
+}
+
+// TaskChange is what a command did to a single task.
+type TaskChange struct {
+	TaskId string `json:"task_id"`
+	// Before is nil if the command created the task.
+	Before *TaskSnapshot `json:"before"`
+	// After is nil if the command deleted the task.
+	After *TaskSnapshot `json:"after"`
+}
+
+// TaskSnapshot is a task along with where it was on the board.
+type TaskSnapshot struct {
+	Task *Task `json:"task"`
+	// Shelf is set if the task was in the archive or the trash rather than in a
+	// column. Column is then the column it was taken from.
+	Shelf TaskShelf `json:"shelf,omitempty"`
+	// Column is the ID of the column. Snapshots taken before columns had IDs have
+	// its name instead.
+	Column string `json:"column"`
+	// ColumnName is the name the column had when the snapshot was taken, for the
+	// history of the task.
+	ColumnName string `json:"column_name,omitempty"`
+	// NextOccurrenceKey is the key the next occurrence of a recurring task had when the
+	// snapshot was taken, for the history of the task.
+	NextOccurrenceKey string `json:"next_occurrence_key,omitempty"`
+	Position   int    `json:"position"`
+	// RemovedAt is set if the task was in the archive or the trash.
+	RemovedAt  *time.Time `json:"removed_at,omitempty"`
+}
+
+// getColumnName returns the name the column of the snapshot had.
+func (snapshot *TaskSnapshot) getColumnName() string {
+	if snapshot.ColumnName != "" {
+		return snapshot.ColumnName
+	}
+
+	return snapshot.Column
+}
+
+// runCommand makes a change to the board through the given function and saves it,
+// recording it to the board's history so it can be undone. The IDs are of every
+// task the function touches.
+func (self *UserConfig) runCommand(board *Board, description string, taskIds []string, mutate func() error) error {
+	before := takeTaskSnapshots(board, taskIds)
+	rollBack := board.saveForRollBack(taskIds, before)
+
+	// The tasks are kept as well, for what the command adds to the history of the
+	// ones it takes off the board.
+	tasks := map[string]*Task{}
+	for _, taskId := range taskIds {
+		tasks[taskId] = board.findTask(taskId)
+	}
+
+	err := mutate()
+	if err != nil {
+		return err
+	}
+
+	// Tasks new to the board get their key.
+	for _, taskId := range taskIds {
+		if before[taskId] != nil {
+			continue
+		}
+
+		if task := board.findTask(taskId); task != nil {
+			board.assignTaskNumber(task)
+		}
+	}
+
+	// Every task the command touched gets what happened to it added to its history,
+	// and its timestamps updated.
+	afterMutate := takeTaskSnapshots(board, taskIds)
+	removalEvents := map[string][]*TaskEvent{}
+	now := timestampNow()
+	for _, taskId := range taskIds {
+		if before[taskId] != nil && afterMutate[taskId] == nil {
+			// The history of a task that's gone can only be read from the task itself,
+			// like for where it was moved to, which isn't a delete.
+			gone := *before[taskId]
+			if tasks[taskId] != nil {
+				gone.Task = tasks[taskId]
+			}
+			events := taskEventsBetween(&gone, nil, false)
+
+			removalEvents[taskId] = append(slices.Clone(gone.Task.Events[len(before[taskId].Task.Events):]), events...)
+			continue
+		}
+
+		events := taskEventsBetween(before[taskId], afterMutate[taskId], false)
+		board.addTaskEvents(taskId, events)
+		board.updateTaskTimestamps(before[taskId], afterMutate[taskId], now)
+	}
+
+	err = self.UpdateBoard(board)
+	if err != nil {
+		// Nothing of the change was saved, and it isn't in the history to be undone,
+		// so it's taken back off the board as well.
+		rollBack()
+		return err
+	}
+
+	for taskId, events := range removalEvents {
+		// The task is gone from the board along with its history. Undoing brings
+		// it back with the history from before, so its removal, along with what the
+		// command added to its history, is kept there.
+		before[taskId].Task.Events = append(before[taskId].Task.Events, events...)
+	}
+
+	// Saving can merge in changes from another process, like the tasks added here
+	// getting new keys, so the tasks are taken as they were saved.
+	after := takeTaskSnapshots(board, taskIds)
+
+	command := new(Command)
+	command.Description = description
+	command.At = timestampNow()
+	command.Changes = []*TaskChange{}
+
+	for _, taskId := range taskIds {
+		command.Changes = append(command.Changes, &TaskChange{
+			TaskId: taskId,
+			Before: before[taskId],
+			After:  after[taskId],
+		})
+	}
+
+	history, err := self.GetHistory(board.Id)
+	if err != nil {
+		return err
+	}
+
+	history.Undo = append(history.Undo, command)
+	if len(history.Undo) > historyLimit {
+		history.Undo = history.Undo[len(history.Undo)-historyLimit:]
+	}
+	// A new command makes whatever was undone before it impossible to redo.
+	history.Redo = []*Command{}
+
+	return self.saveHistory(board, history)
+}
+
+// Undo reverts the last command done on the board and saves it. It returns the
+// command that was undone, or None if there was nothing to undo.
+func (self *UserConfig) Undo(board *Board) (opt.Option[*Command], error) {
+	history, err := self.GetHistory(board.Id)
+	if err != nil {
+		return opt.None[*Command](), err
+	}
+
+	if len(history.Undo) == 0 {
+		return opt.None[*Command](), nil
+	}
+
+	command := history.Undo[len(history.Undo)-1]
+	rollBack := board.saveForRollBack(command.getTaskIds(), nil)
+
+	for i := len(command.Changes) - 1; i >= 0; i-- {
+		change := command.Changes[i]
+		board.applyTaskSnapshot(change.TaskId, change.Before)
+	}
+
+	err = self.UpdateBoard(board)
+	if err != nil {
+		rollBack()
+		return opt.None[*Command](), err
+	}
+
+	history.Undo = history.Undo[:len(history.Undo)-1]
+	history.Redo = append(history.Redo, command)
+
+	err = self.saveHistory(board, history)
+	if err != nil {
+		return opt.None[*Command](), err
+	}
+
+	return opt.Some(command), nil
+}
+
+// Redo does the last undone command on the board again and saves it. It returns
+// the command that was redone, or None if there was nothing to redo.
+func (self *UserConfig) Redo(board *Board) (opt.Option[*Command], error) {
+	history, err := self.GetHistory(board.Id)
+	if err != nil {
+		return opt.None[*Command](), err
+	}
+
+	if len(history.Redo) == 0 {
+		return opt.None[*Command](), nil
+	}
+
+	command := history.Redo[len(history.Redo)-1]
+	rollBack := board.saveForRollBack(command.getTaskIds(), nil)
+
+	for _, change := range command.Changes {
+		board.applyTaskSnapshot(change.TaskId, change.After)
+	}
+
+	err = self.UpdateBoard(board)
+	if err != nil {
+		rollBack()
+		return opt.None[*Command](), err
+	}
+
+	history.Redo = history.Redo[:len(history.Redo)-1]
+	history.Undo = append(history.Undo, command)
+
+	err = self.saveHistory(board, history)
+	if err != nil {
+		return opt.None[*Command](), err
+	}
+
+	return opt.Some(command), nil
+}
+
+// getTaskIds returns the IDs of the tasks the command touched.
+func (command *Command) getTaskIds() []string {
+	ret := []string{}
+
+	for _, change := range command.Changes {
+		ret = append(ret, change.TaskId)
+	}
+
+	return ret
+}
+
+// GetHistory returns the history of the board with the given ID, reading it from
+// the store only the first time it's asked for.
+func (self *UserConfig) GetHistory(boardId string) (*History, error) {
+	if history, ok := self.histories[boardId]; ok {
+		return history, nil
+	}
+
+	history, err := self.store.LoadHistory(boardId)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the history of the board. %s", err)
+	}
+
+	self.histories[boardId] = history
+
+	return history, nil
+}
+
+// saveHistory saves the history of the given board. Stores that keep the history
+// along with the board can count it as a change to the board, so its revision is
+// remembered again, unless someone else changed the board in the meantime.
+func (self *UserConfig) saveHistory(board *Board, history *History) error {
+	unlock, err := self.store.Lock()
+	if err != nil {
+		return fmt.Errorf("Failed to lock the store on saving the history. %s", err)
+	}
+	defer unlock()
+
+	changed, err := self.HasBoardChanged(board)
+	if err != nil {
+		return err
+	}
+
+	err = self.store.SaveHistory(history)
+	if err != nil {
+		return fmt.Errorf("Failed to save the history of the board. %s", err)
+	}
+
+	if !changed {
+		self.rememberRevision(board.Id)
+	}
+
+	return nil
+}
+
+// addTaskEvents appends the events to the history of the task with the given ID,
+// if it's on the board or in its archive or trash.
+func (board *Board) addTaskEvents(taskId string, events []*TaskEvent) {
+	task := board.findTask(taskId)
+	if task == nil {
+		return
+	}
+
+	task.Events = append(task.Events, events...)
+}
+
+// takeTaskSnapshots copies the tasks with the given IDs along with where they are.
+// Tasks that aren't on the board, or in its archive or trash, are left out.
+func takeTaskSnapshots(board *Board, taskIds []string) map[string]*TaskSnapshot {
+	ret := map[string]*TaskSnapshot{}
+
+	for _, taskId := range taskIds {
+		for columnId, tasks := range board.Tasks {
+			for i, task := range tasks {
+				if task.Id == taskId {
+					ret[taskId] = &TaskSnapshot{
+						Task:       task.copy(),
+						Column:     columnId,
+						ColumnName: board.GetColumnName(columnId),
+						Position:   i,
+					}
+					ret[taskId].NextOccurrenceKey, _ = board.GetOccurrenceKey(task.NextOccurrenceId)
+				}
+			}
+		}
+
+		for _, shelf := range []TaskShelf{ArchiveShelf, TrashShelf} {
+			for i, it := range board.GetShelf(shelf) {
+				if it.Task.Id == taskId {
+					removedAt := it.RemovedAt
+					ret[taskId] = &TaskSnapshot{
+						Task:       it.Task.copy(),
+						Shelf:      shelf,
+						Column:     it.Column,
+						ColumnName: board.GetColumnName(it.Column),
+						Position:   i,
+						RemovedAt:  &removedAt,
+					}
+					ret[taskId].NextOccurrenceKey, _ = board.GetOccurrenceKey(it.Task.NextOccurrenceId)
+				}
+			}
+		}
+	}
+
+	return ret
+}
+
+// applyTaskSnapshot puts the task with the given ID back to how it is in the
+// snapshot, or removes it from the board if the snapshot is nil. A task that's
+// still on the board is updated in place so pointers to it stay valid. The history
+// of the task isn't rolled back, what the snapshot changed is added to it instead.
+func (board *Board) applyTaskSnapshot(taskId string, snapshot *TaskSnapshot) {
+	current := takeTaskSnapshots(board, []string{taskId})[taskId]
+	events := taskEventsBetween(current, snapshot, true)
+
+	existing := board.findTask(taskId)
+
+	board.removeTaskById(taskId)
+
+	if snapshot == nil {
+		return
+	}
+
+	task := existing
+	history := snapshot.Task.Events
+	if task == nil {
+		task = new(Task)
+	} else {
+		history = task.Events
+	}
+
+	*task = *snapshot.Task.copy()
+	task.Events = append(slices.Clone(history), events...)
+	// The task keeps when it was started and completed as of the snapshot, but
+	// undoing or redoing is a change to it.
+	if len(events) > 0 {
+		task.UpdatedAt = timestampNow()
+	}
+
+	board.placeTask(task, snapshot)
+}
+
+// saveForRollBack takes what's needed to put the tasks with the given IDs back as
+// they are now, along with the columns and the keys of the board, if a change to
+// them can't be saved. The snapshots of the tasks are taken if they aren't given.
+// It returns the function that puts them back, exactly and without adding to the
+// history of the tasks.
+func (board *Board) saveForRollBack(taskIds []string, snapshots map[string]*TaskSnapshot) func() {
+	if snapshots == nil {
+		snapshots = takeTaskSnapshots(board, taskIds)
+	}
+
+	// The tasks are put back in place, so pointers to them stay valid even for the
+	// ones the change took off the board.
+	tasks := map[string]*Task{}
+	for _, taskId := range taskIds {
+		tasks[taskId] = board.findTask(taskId)
+	}
+
+	columns := slices.Clone(board.Columns)
+	taskKeyPrefix := board.TaskKeyPrefix
+	lastTaskNumber := board.LastTaskNumber
+
+	return func() {
+		board.Columns = columns
+		board.TaskKeyPrefix = taskKeyPrefix
+		board.LastTaskNumber = lastTaskNumber
+
+		for _, taskId := range taskIds {
+			board.removeTaskById(taskId)
+		}
+
+		// The tasks go back in the order of their positions, so each lands where it was.
+		sorted := []string{}
+		for _, taskId := range taskIds {
+			if snapshots[taskId] != nil {
+				sorted = append(sorted, taskId)
+			}
+		}
+		slices.SortStableFunc(sorted, func(a string, b string) int {
+			return snapshots[a].Position - snapshots[b].Position
+		})
+
+		for _, taskId := range sorted {
+			snapshot := snapshots[taskId]
+			task := tasks[taskId]
+			if task == nil {
+				task = new(Task)
+			}
+			*task = *snapshot.Task.copy()
+
+			board.placeTask(task, snapshot)
+		}
+	}
+}
+
+// removeTaskById takes the task with the given ID off the board, out of its column
+// or out of the archive or the trash.
+func (board *Board) removeTaskById(taskId string) {
+	for columnId, tasks := range board.Tasks {
+		for i, task := range tasks {
+			if task.Id == taskId {
+				board.Tasks[columnId] = append(tasks[:i:i], tasks[i+1:]...)
+				break
+			}
+		}
+	}
+	board.removeTaskFromShelf(taskId, ArchiveShelf)
+	board.removeTaskFromShelf(taskId, TrashShelf)
+}
+
+// placeTask puts the task where the snapshot has it, in its column or on its shelf.
+func (board *Board) placeTask(task *Task, snapshot *TaskSnapshot) {
+	if snapshot.Shelf != OnBoard {
+		shelf := board.shelf(snapshot.Shelf)
+		position := min(max(snapshot.Position, 0), len(*shelf))
+
+		removed := &RemovedTask{
+			Task:      task,
+			Column:    snapshot.Column,
+			RemovedAt: timestampNow(),
+		}
+		if snapshot.RemovedAt != nil {
+			removed.RemovedAt = *snapshot.RemovedAt
+		}
+		*shelf = append((*shelf)[:position:position], append([]*RemovedTask{removed}, (*shelf)[position:]...)...)
+
+		return
+	}
+
+	columnId := snapshot.Column
+	if !board.hasColumn(columnId) {
+		column, err := board.FindColumn(snapshot.Column)
+		if err == nil {
+			// The snapshot is from before columns had IDs.
+			columnId = column.Id
+		} else if len(board.Columns) > 0 {
+			// The column was removed since. Keep the task on the board anyway.
+			columnId = board.GetBacklogColumn().Id
+		} else {
+			return
+		}
+	}
+
+	tasks := board.Tasks[columnId]
+	position := min(max(snapshot.Position, 0), len(tasks))
+
+	board.Tasks[columnId] = append(tasks[:position:position], append([]*Task{task}, tasks[position:]...)...)
+}
+
+// findTask searches the columns, the archive and the trash for the task with the given ID.
+func (board *Board) findTask(taskId string) *Task {
+	taskOpt := board.GetTaskById(taskId)
+	if taskOpt.IsSome() {
+		return taskOpt.Unwrap()
+	}
+
+	removedOpt, _ := board.GetRemovedTaskById(taskId)
+	if removedOpt.IsSome() {
+		return removedOpt.Unwrap().Task
+	}
+
+	return nil
+}
+
+func (board *Board) hasColumn(columnId string) bool {
+	columnOpt := board.GetColumnById(columnId)
+
+	return columnOpt.IsSome()
+}
@@ -0,0 +1,240 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// failingStore is a memory store that refuses to save boards once failSaves is set,
+// like a store on a full disk, or to save only the board with the ID failBoardId.
+type failingStore struct {
+	*MemoryStore
+	failSaves   bool
+	failBoardId string
+}
+
+func (self *failingStore) SaveBoard(board *Board) error {
+	if self.failSaves || board.Id == self.failBoardId {
+		return errors.New("The disk is full")
+	}
+
+	return self.MemoryStore.SaveBoard(board)
+}
+
+// testBoardJSON serializes the whole board, leaving out the columns without tasks
+// so a column that was emptied compares equal to one that never had tasks.
+func testBoardJSON(t *testing.T, board *Board) string {
+	t.Helper()
+
+	copied := board.clone()
+	for columnId, tasks := range copied.Tasks {
+		if len(tasks) == 0 {
+			delete(copied.Tasks, columnId)
+		}
+	}
+
+	content, err := json.Marshal(copied)
+	if err != nil {
+		t.Fatalf("Failed to serialize the board. %s", err)
+	}
+
+	return string(content)
+}
+
+// historyTestCases are changes to a board with "one" and "two" in its backlog,
+// along with how the board looks once they're done and once they're undone.
+var historyTestCases = []struct {
+	name   string
+	change func(t *testing.T, config *UserConfig, board *Board) error
+	done   func(t *testing.T, board *Board)
+	undone func(t *testing.T, board *Board)
+}{
+	{
+		name: "adding a task",
+		change: func(t *testing.T, config *UserConfig, board *Board) error {
+			return config.AddTask(board.Id, NewTask("three", ""))
+		},
+		done: func(t *testing.T, board *Board) {
+			task, _ := mustFindTestTask(t, board, "three")
+			if board.GetTaskKey(task) != "API-3" {
+				t.Errorf("Expected the task to be API-3, got %s", board.GetTaskKey(task))
+			}
+		},
+		undone: func(t *testing.T, board *Board) {
+			if task, _ := findTestTask(board, "three"); task != nil {
+				t.Errorf("Expected the task to be taken off the board")
+			}
+		},
+	},
+	{
+		name: "moving a task",
+		change: func(t *testing.T, config *UserConfig, board *Board) error {
+			task, _ := mustFindTestTask(t, board, "one")
+			return config.MoveTaskRight(board, task, PlaceOnTop)
+		},
+		done: func(t *testing.T, board *Board) {
+			if _, column := mustFindTestTask(t, board, "one"); column.Name != "In Progress" {
+				t.Errorf("Expected the task to be in In Progress, got %s", column.Name)
+			}
+		},
+		undone: func(t *testing.T, board *Board) {
+			if _, column := mustFindTestTask(t, board, "one"); column.Role != BacklogRole {
+				t.Errorf("Expected the task to be back in the backlog, got %s", column.Name)
+			}
+		},
+	},
+	{
+		name: "deleting a task",
+		change: func(t *testing.T, config *UserConfig, board *Board) error {
+			task, _ := mustFindTestTask(t, board, "two")
+			return config.DeleteTask(board.Id, task)
+		},
+		done: func(t *testing.T, board *Board) {
+			if task, _ := findTestTask(board, "two"); task != nil {
+				t.Errorf("Expected the task to be taken off the board")
+			}
+		},
+		undone: func(t *testing.T, board *Board) {
+			task, _ := mustFindTestTask(t, board, "two")
+			if board.GetTaskKey(task) != "API-2" {
+				t.Errorf("Expected the task to keep its key, got %s", board.GetTaskKey(task))
+			}
+		},
+	},
+}
+
+func TestUndoAndRedo(t *testing.T) {
+	for _, test := range historyTestCases {
+		t.Run(test.name, func(t *testing.T) {
+			config, board := newTestConfig(t)
+			addTestTask(t, config, board, "one")
+			addTestTask(t, config, board, "two")
+
+			mustSucceed(t, test.change(t, config, board))
+			test.done(t, board)
+
+			undone, err := config.Undo(board)
+			if err != nil || undone.IsNone() {
+				t.Fatalf("Expected the change to be undone, got %v", err)
+			}
+			test.undone(t, board)
+
+			redone, err := config.Redo(board)
+			if err != nil || redone.IsNone() {
+				t.Fatalf("Expected the change to be redone, got %v", err)
+			}
+			test.done(t, board)
+
+			// The history is kept in the store, so a config read again, like after a
+			// restart, can still undo the change.
+			reopened := reopenConfig(t, config)
+			reopenedBoard := openTestBoard(t, reopened, board.Id)
+			test.done(t, reopenedBoard)
+
+			undone, err = reopened.Undo(reopenedBoard)
+			if err != nil || undone.IsNone() {
+				t.Fatalf("Expected the change to be undone after reopening, got %v", err)
+			}
+			test.undone(t, reopenedBoard)
+			test.undone(t, openTestBoard(t, reopenConfig(t, reopened), board.Id))
+		})
+	}
+}
+
+func TestChangesThatFailToSaveAreRolledBack(t *testing.T) {
+	for _, test := range historyTestCases {
+		t.Run(test.name, func(t *testing.T) {
+			store := &failingStore{MemoryStore: NewMemoryStore()}
+			config, err := SetupUserConfigInStore(store)
+			if err != nil {
+				t.Fatalf("Failed to set up the config. %s", err)
+			}
+			board, err := config.CreateBoard("api", "/projects/api")
+			if err != nil {
+				t.Fatalf("Failed to create the board. %s", err)
+			}
+			one := addTestTask(t, config, board, "one")
+			two := addTestTask(t, config, board, "two")
+
+			history, err := config.GetHistory(board.Id)
+			if err != nil {
+				t.Fatalf("Failed to read the history. %s", err)
+			}
+			commands := len(history.Undo)
+			before := testBoardJSON(t, board)
+
+			store.failSaves = true
+			err = test.change(t, config, board)
+			if err == nil {
+				t.Fatalf("Expected the change to fail to be saved")
+			}
+
+			if after := testBoardJSON(t, board); after != before {
+				t.Errorf("Expected the board to be rolled back\nbefore: %s\nafter:  %s", before, after)
+			}
+			if len(history.Undo) != commands {
+				t.Errorf("Expected nothing to be added to the history, got %d commands", len(history.Undo))
+			}
+			// Tasks are put back in place, so pointers to them stay valid.
+			for _, task := range []*Task{one, two} {
+				if _, i := board.GetColumnForTask(task); i == -1 {
+					t.Errorf("Expected \"%s\" to be the same task as before", task.Title)
+				}
+			}
+
+			// Undoing and redoing a change roll back the same way.
+			store.failSaves = false
+			mustSucceed(t, test.change(t, config, board))
+			done := testBoardJSON(t, board)
+
+			store.failSaves = true
+			_, err = config.Undo(board)
+			if err == nil {
+				t.Fatalf("Expected undoing to fail to be saved")
+			}
+			if after := testBoardJSON(t, board); after != done {
+				t.Errorf("Expected undoing to be rolled back\nbefore: %s\nafter:  %s", done, after)
+			}
+			test.done(t, board)
+		})
+	}
+}
+
+func TestMoveTaskThatIsNotOnTheBoard(t *testing.T) {
+	config, board := newTestConfig(t)
+	task := NewTask("one", "")
+
+	if err := config.MoveTaskRight(board, task, PlaceOnTop); err == nil {
+		t.Errorf("Expected moving a task that isn't on the board right to fail")
+	}
+	if err := config.MoveTaskLeft(board, task, PlaceOnTop); err == nil {
+		t.Errorf("Expected moving a task that isn't on the board left to fail")
+	}
+}
+
+func TestEmptyTrashThatFailsToBeSaved(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore()}
+	config, err := SetupUserConfigInStore(store)
+	if err != nil {
+		t.Fatalf("Failed to set up the config. %s", err)
+	}
+	board, err := config.CreateBoard("api", "/projects/api")
+	if err != nil {
+		t.Fatalf("Failed to create the board. %s", err)
+	}
+	task := addTestTask(t, config, board, "one")
+	mustSucceed(t, config.DeleteTask(board.Id, task))
+
+	store.failSaves = true
+	_, err = config.EmptyTrash(board.Id)
+	if err == nil {
+		t.Fatalf("Expected emptying the trash to fail to be saved")
+	}
+
+	// The task is put back in the trash as the same task, even though it was gone
+	// from the board.
+	if len(board.Trash) != 1 || board.Trash[0].Task != task {
+		t.Errorf("Expected the task to be put back in the trash")
+	}
+}
@@ -0,0 +1,310 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/okira-e/gotasks/internal/opt"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// LocalBoardDirName is the folder in a project's root that holds its local board.
+const LocalBoardDirName = ".gotasks"
+
+// LocalBoardFileName is the name of the local board's file inside LocalBoardDirName.
+const LocalBoardFileName = "board.json"
+
+// GetLocalBoardFilePath returns the path of the local board file of the given
+// directory if it has one.
+func GetLocalBoardFilePath(dirPath string) opt.Option[string] {
+	filePath := filepath.Join(dirPath, LocalBoardDirName, LocalBoardFileName)
+
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
+		return opt.None[string]()
+	}
+
+	return opt.Some(filePath)
+}
+
+// CreateLocalBoard creates a local board file in the given directory. If a board
+// is given, its columns and tasks are copied to the new one.
+// It returns the path of the new file.
+func CreateLocalBoard(dirPath string, from *Board) (string, error) {
+	filePath := filepath.Join(dirPath, LocalBoardDirName, LocalBoardFileName)
+
+	if _, err := os.Stat(filePath); err == nil {
+		return "", fmt.Errorf("%s already exists", filePath)
+	}
+
+	board := new(Board)
+	board.SchemaVersion = CurrentSchemaVersion
+	board.Id = uuid.New().String()
+	board.Name = filepath.Base(dirPath)
+	board.TaskKeyPrefix = DefaultTaskKeyPrefix(board.Name)
+	board.Columns = defaultColumns()
+	board.Tasks = map[string][]*Task{}
+
+	if from != nil {
+		board.Name = from.Name
+		board.Columns = from.Columns
+		board.Tasks = from.Tasks
+		board.TaskKeyPrefix = from.TaskKeyPrefix
+		board.LastTaskNumber = from.LastTaskNumber
+		board.StrictWipLimits = from.StrictWipLimits
+		board.Swimlanes = from.Swimlanes
+		board.Templates = from.Templates
+		board.ShowTaskAges = from.ShowTaskAges
+	}
+
+	err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeLocalBoardFile(filePath, board)
+	if err != nil {
+		return "", err
+	}
+
+	return filePath, nil
+}
+
+// LocalBoardStore is a store for a board that lives in a project's own repository
+// rather than with the user's config, so it can be committed and shared. Everything
+// other than that board goes to the store it wraps.
+type LocalBoardStore struct {
+	Store
+
+	filePath  string
+	rootDir   string
+	boardId   string
+	boardName string
+}
+
+// NewLocalBoardStore returns a store that serves the board in the given local
+// board file on top of the given store.
+func NewLocalBoardStore(inner Store, filePath string) (*LocalBoardStore, error) {
+	board, err := upgradeLocalBoardFile(inner, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the local board at %s. %s", filePath, err)
+	}
+
+	ret := new(LocalBoardStore)
+
+	ret.Store = inner
+	ret.filePath = filePath
+	ret.rootDir = filepath.Dir(filepath.Dir(filePath))
+	ret.boardId = board.Id
+	ret.boardName = board.Name
+
+	return ret, nil
+}
+
+// LoadConfig adds the local board to the index first, so it's found before any
+// board of the user's with the same name.
+func (self *LocalBoardStore) LoadConfig() (*UserConfig, error) {
+	config, err := self.Store.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	config.Boards = append([]*BoardEntry{self.entry()}, config.Boards...)
+
+	return config, nil
+}
+
+// SaveConfig leaves the local board out of the index it saves.
+func (self *LocalBoardStore) SaveConfig(config *UserConfig) error {
+	configToSave := *config
+	configToSave.Boards = []*BoardEntry{}
+
+	for _, entry := range config.Boards {
+		if entry.Id != self.boardId {
+			configToSave.Boards = append(configToSave.Boards, entry)
+		}
+	}
+
+	return self.Store.SaveConfig(&configToSave)
+}
+
+func (self *LocalBoardStore) LoadBoard(boardId string) (*Board, error) {
+	if boardId != self.boardId {
+		return self.Store.LoadBoard(boardId)
+	}
+
+	board, err := readLocalBoardFile(self.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The board's ID was made up when the file had none. Keep it the same one.
+	board.Id = self.boardId
+	board.Dir = self.rootDir
+
+	return board, nil
+}
+
+func (self *LocalBoardStore) SaveBoard(board *Board) error {
+	if board.Id != self.boardId {
+		return self.Store.SaveBoard(board)
+	}
+
+	return writeLocalBoardFile(self.filePath, board)
+}
+
+func (self *LocalBoardStore) Revision(boardId string) (string, error) {
+	if boardId != self.boardId {
+		return self.Store.Revision(boardId)
+	}
+
+	info, err := os.Stat(self.filePath)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size()), nil
+}
+
+// The rest of documentStore goes to the wrapped store. The local board isn't part
+// of its index, so it's migrated when the store is made instead. See upgradeLocalBoardFile.
+
+func (self *LocalBoardStore) loadConfigDocument() (document, error) {
+	return self.innerDocumentStore().loadConfigDocument()
+}
+
+func (self *LocalBoardStore) saveConfigDocument(config document) error {
+	return self.innerDocumentStore().saveConfigDocument(config)
+}
+
+func (self *LocalBoardStore) loadBoardDocument(boardId string) (document, error) {
+	return self.innerDocumentStore().loadBoardDocument(boardId)
+}
+
+func (self *LocalBoardStore) saveBoardDocument(boardId string, board document) error {
+	return self.innerDocumentStore().saveBoardDocument(boardId, board)
+}
+
+func (self *LocalBoardStore) backupAll(label string) error {
+	return self.innerDocumentStore().backupAll(label)
+}
+
+// innerDocumentStore returns the wrapped store as a documentStore. Stores that
+// can't be migrated are given as one that has nothing to migrate.
+func (self *LocalBoardStore) innerDocumentStore() documentStore {
+	if ret, ok := self.Store.(documentStore); ok {
+		return ret
+	}
+
+	return NewMemoryStore()
+}
+
+func (self *LocalBoardStore) entry() *BoardEntry {
+	return &BoardEntry{
+		Id:   self.boardId,
+		Name: self.boardName,
+		Dir:  self.rootDir,
+	}
+}
+
+// upgradeLocalBoardFile reads the local board file and, if it's at an older schema
+// version or has no ID, writes it back migrated, under the lock of the given store.
+// Migrating makes up what the file is missing, like the IDs of its tasks, so it has
+// to be done once rather than on every read, or two reads of the same file would
+// disagree on what tasks it has.
+func upgradeLocalBoardFile(store Store, filePath string) (*Board, error) {
+	unlock, err := store.Lock()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to lock the store for reading the local board. %s", err)
+	}
+	defer unlock()
+
+	doc, err := readJSONDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	boardId, _ := doc["id"].(string)
+	isUpToDate := documentSchemaVersion(doc) == CurrentSchemaVersion && boardId != ""
+
+	board, err := decodeLocalBoardDocument(filePath, doc)
+	if err != nil || isUpToDate {
+		return board, err
+	}
+
+	utils.SaveLog(utils.Info, "Upgrading the local board", map[string]any{"path": filePath, "version": documentSchemaVersion(doc)})
+
+	err = writeLocalBoardFile(filePath, board)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write the upgraded local board. %s", err)
+	}
+
+	return board, nil
+}
+
+// readLocalBoardFile reads a local board file, migrating it to the current schema
+// version in memory if it was changed to an older one since it was upgraded.
+func readLocalBoardFile(filePath string) (*Board, error) {
+	doc, err := readJSONDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLocalBoardDocument(filePath, doc)
+}
+
+// decodeLocalBoardDocument migrates the document of the local board file at the given
+// path and reads it into a Board, filling in the ID and the name if it has none.
+func decodeLocalBoardDocument(filePath string, doc document) (*Board, error) {
+	board, err := decodeBoardDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if board.Id == "" {
+		board.Id = uuid.New().String()
+	}
+	if board.Name == "" {
+		board.Name = filepath.Base(filepath.Dir(filepath.Dir(filePath)))
+	}
+
+	return board, nil
+}
+
+// writeLocalBoardFile writes the board to a local board file. The directory is
+// left out since the file is shared between everyone who clones the project.
+func writeLocalBoardFile(filePath string, board *Board) error {
+	boardToSave := *board
+	boardToSave.Dir = ""
+
+	fileContent, err := json.MarshalIndent(boardToSave, "", "\t")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal the board. %s", err)
+	}
+
+	return utils.WriteFileAtomic(filePath, fileContent, 0644)
+}
+
+// GetUserConfigWithLocalBoard is GetUserConfig with the local board in the given
+// file added to it. It returns the ID of the local board as well.
+func GetUserConfigWithLocalBoard(filePath string) (*UserConfig, string, error) {
+	store, err := GetStoreBasedOnEnv()
+	if err != nil {
+		return nil, "", err
+	}
+
+	localStore, err := NewLocalBoardStore(store, filePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	config, err := GetUserConfigFromStore(localStore)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return config, localStore.boardId, nil
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestNewLocalBoardStoreUpgradesTheFile(t *testing.T) {
+	tests := []struct {
+		name string
+		// boardJSON is what the local board file starts with.
+		boardJSON string
+		// upgraded is whether the file is expected to be written back.
+		upgraded bool
+	}{
+		{
+			name: "a board from before tasks were sure to have IDs",
+			boardJSON: `{
+				"schema_version": 1,
+				"name": "api",
+				"columns": ["Todo", "Done"],
+				"tasks": {
+					"Todo": [{"title": "Without an ID"}, {"title": "Without a creation time either"}],
+					"Done": [{"id": "done", "title": "Done", "created_at": "2024-01-01T00:00:00Z"}]
+				}
+			}`,
+			upgraded: true,
+		},
+		{
+			name: "a board at the current version without an ID",
+			boardJSON: `{
+				"schema_version": ` + strconv.Itoa(CurrentSchemaVersion) + `,
+				"name": "api",
+				"columns": [{"id": "todo", "name": "Todo", "role": "backlog"}],
+				"tasks": {"todo": [{"id": "1", "title": "Task", "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}]}
+			}`,
+			upgraded: true,
+		},
+		{
+			name: "a board at the current version",
+			boardJSON: `{
+				"schema_version": ` + strconv.Itoa(CurrentSchemaVersion) + `,
+				"id": "board",
+				"name": "api",
+				"columns": [{"id": "todo", "name": "Todo", "role": "backlog"}],
+				"tasks": {"todo": [{"id": "1", "title": "Task", "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}]}
+			}`,
+			upgraded: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), LocalBoardDirName, LocalBoardFileName)
+			err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+			if err != nil {
+				t.Fatalf("Failed to create the folder of the board. %s", err)
+			}
+			err = os.WriteFile(filePath, []byte(test.boardJSON), 0644)
+			if err != nil {
+				t.Fatalf("Failed to write the board. %s", err)
+			}
+
+			store, err := NewLocalBoardStore(NewMemoryStore(), filePath)
+			if err != nil {
+				t.Fatalf("Failed to read the local board. %s", err)
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("Failed to read the board file. %s", err)
+			}
+			if written := !bytes.Equal(content, []byte(test.boardJSON)); written != test.upgraded {
+				t.Fatalf("Expected the file to be written back: %v, got %v", test.upgraded, written)
+			}
+
+			// Every read has to agree on the board, or saving it would merge the
+			// tasks of one read with the ones of the other as if they were new.
+			first, err := store.LoadBoard(store.boardId)
+			if err != nil {
+				t.Fatalf("Failed to load the board. %s", err)
+			}
+			second, err := store.LoadBoard(store.boardId)
+			if err != nil {
+				t.Fatalf("Failed to load the board again. %s", err)
+			}
+
+			if first.SchemaVersion != CurrentSchemaVersion {
+				t.Errorf("Expected the board to be at schema version %d, got %d", CurrentSchemaVersion, first.SchemaVersion)
+			}
+			if string(boardMetaJSON(first)) != string(boardMetaJSON(second)) {
+				t.Errorf("Expected two reads of the board to agree\nfirst:  %s\nsecond: %s", boardMetaJSON(first), boardMetaJSON(second))
+			}
+
+			firstTasks, _ := indexTasks(first)
+			secondTasks, _ := indexTasks(second)
+			if len(firstTasks) == 0 || len(firstTasks) != len(secondTasks) {
+				t.Fatalf("Expected both reads to have the same tasks, got %d and %d", len(firstTasks), len(secondTasks))
+			}
+			for taskId, task := range firstTasks {
+				if other, ok := secondTasks[taskId]; !ok || !tasksEqual(task, other) {
+					t.Errorf("Expected \"%s\" to be the same in both reads", task.Title)
+				}
+			}
+
+			// A store made again, like by another process, finds the same board.
+			again, err := NewLocalBoardStore(NewMemoryStore(), filePath)
+			if err != nil {
+				t.Fatalf("Failed to read the local board again. %s", err)
+			}
+			if again.boardId != store.boardId {
+				t.Errorf("Expected the board to keep its ID, got %s and %s", store.boardId, again.boardId)
+			}
+		})
+	}
+}
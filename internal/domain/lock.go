@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// lockTimeout is how long to wait for another process to release the lock before giving up.
+const lockTimeout = 5 * time.Second
+
+// lockRetryDelay is how often the lock is retried while another process holds it.
+const lockRetryDelay = 20 * time.Millisecond
+
+// lockFile takes an advisory lock on the file at the given path, creating it if
+// needed. It returns the function that releases it.
+func lockFile(filePath string) (func(), error) {
+	fileLock := flock.New(filePath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	locked, err := fileLock.TryLockContext(ctx, lockRetryDelay)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+
+	if !locked {
+		return nil, errors.New("Timed out waiting for another gotasks to release the lock")
+	}
+
+	return func() {
+		fileLock.Unlock()
+	}, nil
+}
@@ -0,0 +1,273 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// mergeBoards merges the changes another process saved (theirs) into the changes
+// made here (ours), both made on top of the same base. The merge is done per task,
+// keyed by Task.Id, and is written into ours in place. Task pointers in ours stay
+// valid for the tasks that are still on the board.
+//
+// For every task, its content and its column are resolved on their own: whichever
+// side changed it from the base wins, and ours wins if both did. A task deleted by
+// one side stays deleted unless the other side changed it.
+func mergeBoards(base *Board, ours *Board, theirs *Board) {
+	baseTasks, baseColumns := indexTasks(base)
+	ourTasks, ourColumns := indexTasks(ours)
+	theirTasks, theirColumns := indexTasks(theirs)
+
+	ourArchive, ourTrash := ours.Archive, ours.Trash
+	ourLastTaskNumber := ours.LastTaskNumber
+
+	// Board fields other than the tasks, like the columns, are merged as a whole.
+	oursMeta := boardMetaJSON(ours)
+	if bytes.Equal(oursMeta, boardMetaJSON(base)) {
+		tasks := ours.Tasks
+		*ours = *theirs
+		ours.Tasks = tasks
+	}
+
+	// resolvedColumns holds the column every task that survives the merge ends up in.
+	resolvedColumns := map[string]string{}
+
+	for taskId, ourTask := range ourTasks {
+		baseTask, inBase := baseTasks[taskId]
+		theirTask, inTheirs := theirTasks[taskId]
+
+		if !inBase {
+			// Created here.
+			resolvedColumns[taskId] = ourColumns[taskId]
+			continue
+		}
+
+		if !inTheirs {
+			// Deleted there. Keep it only if it was changed here.
+			if tasksEqual(ourTask, baseTask) && ourColumns[taskId] == baseColumns[taskId] {
+				continue
+			}
+
+			resolvedColumns[taskId] = ourColumns[taskId]
+			continue
+		}
+
+		// Both histories, and the comments of both sides, are kept whichever side's
+		// content wins.
+		events := mergeTaskEvents(baseTask.Events, ourTask.Events, theirTask.Events)
+		comments := mergeTaskComments(ourTask.Comments, theirTask.Comments)
+		ourTaskAsItWas := ourTask.copy()
+
+		if taskContentEqual(ourTask, baseTask) && !taskContentEqual(theirTask, baseTask) {
+			*ourTask = *theirTask
+		} else if !taskContentEqual(ourTask, baseTask) && !taskContentEqual(theirTask, baseTask) && !taskContentEqual(ourTask, theirTask) {
+			utils.SaveLog(utils.Warn, "A task was changed by two processes, keeping this one's changes", map[string]any{"task": taskId})
+		}
+		ourTask.Events = events
+		ourTask.Comments = comments
+
+		theirColumnWins := ourColumns[taskId] == baseColumns[taskId]
+		resolvedColumns[taskId] = utils.Cond(theirColumnWins, theirColumns[taskId], ourColumns[taskId])
+		mergeTaskTimestamps(ourTask, ourTaskAsItWas, theirTask, theirColumnWins)
+	}
+
+	// Tasks that only exist there are either new or were deleted here.
+	theirOnlyTasks := map[string]*Task{}
+	for taskId, theirTask := range theirTasks {
+		if _, ok := ourTasks[taskId]; ok {
+			continue
+		}
+
+		baseTask, inBase := baseTasks[taskId]
+		if inBase && tasksEqual(theirTask, baseTask) && theirColumns[taskId] == baseColumns[taskId] {
+			continue
+		}
+
+		theirOnlyTasks[taskId] = theirTask
+		resolvedColumns[taskId] = theirColumns[taskId]
+	}
+
+	// Lay the tasks out again. Every column keeps our order, and tasks coming from
+	// there follow in their order, which puts them on top of the column.
+	mergedTasks := map[string][]*Task{}
+	placed := map[string]bool{}
+
+	for _, board := range []*Board{ours, theirs} {
+		for columnId, tasks := range board.Tasks {
+			for _, task := range tasks {
+				if placed[task.Id] {
+					continue
+				}
+
+				resolvedColumn, ok := resolvedColumns[task.Id]
+				if !ok || resolvedColumn != columnId {
+					continue
+				}
+
+				if theirTask, ok := theirOnlyTasks[task.Id]; ok {
+					task = theirTask
+				} else {
+					task = ourTasks[task.Id]
+				}
+
+				mergedTasks[columnId] = append(mergedTasks[columnId], task)
+				placed[task.Id] = true
+			}
+		}
+	}
+
+	ours.Tasks = mergedTasks
+
+	// A task that was taken off the board on one side but changed on the other was
+	// kept on the board above, so it's left out of the archive and the trash.
+	ours.Archive = mergeRemovedTasks(base.Archive, ourArchive, theirs.Archive, placed)
+	ours.Trash = mergeRemovedTasks(base.Trash, ourTrash, theirs.Trash, placed)
+
+	ours.LastTaskNumber = max(ourLastTaskNumber, theirs.LastTaskNumber)
+	renumberClashingTasks(base, ours, theirs)
+
+	// A task can be left in a column that one side deleted while the other side
+	// added the task to it, or moved it there.
+	ours.adoptOrphanedTasks()
+}
+
+// renumberClashingTasks gives new numbers to the tasks added here that got the same
+// number as a task there, since both sides count from the same base. The merged
+// board is ours.
+func renumberClashingTasks(base *Board, ours *Board, theirs *Board) {
+	inBase := map[string]bool{}
+	for _, it := range base.everyTask() {
+		inBase[it.Id] = true
+	}
+
+	theirNumbers := map[int]string{}
+	for _, it := range theirs.everyTask() {
+		theirNumbers[it.Number] = it.Id
+	}
+
+	for _, task := range ours.everyTask() {
+		if inBase[task.Id] || task.Number == 0 {
+			continue
+		}
+
+		if otherId, ok := theirNumbers[task.Number]; ok && otherId != task.Id {
+			ours.LastTaskNumber++
+			task.Number = ours.LastTaskNumber
+		}
+	}
+}
+
+// everyTask returns the tasks on the board along with the ones in its archive and trash.
+func (board *Board) everyTask() []*Task {
+	ret := []*Task{}
+
+	for _, column := range board.Columns {
+		ret = append(ret, board.Tasks[column.Id]...)
+	}
+
+	for _, shelf := range []TaskShelf{ArchiveShelf, TrashShelf} {
+		for _, it := range board.GetShelf(shelf) {
+			ret = append(ret, it.Task)
+		}
+	}
+
+	return ret
+}
+
+// mergeRemovedTasks merges the archives, or the trashes, of two sides by task ID. A
+// task stays if both sides have it or if one side added it, and it's gone if either
+// side took it out. Tasks that are on the board are left out.
+func mergeRemovedTasks(base []*RemovedTask, ours []*RemovedTask, theirs []*RemovedTask, onBoard map[string]bool) []*RemovedTask {
+	inBase := map[string]bool{}
+	for _, it := range base {
+		inBase[it.Task.Id] = true
+	}
+
+	inOurs := map[string]bool{}
+	for _, it := range ours {
+		inOurs[it.Task.Id] = true
+	}
+
+	inTheirs := map[string]bool{}
+	for _, it := range theirs {
+		inTheirs[it.Task.Id] = true
+	}
+
+	ret := []*RemovedTask{}
+
+	for _, it := range ours {
+		if onBoard[it.Task.Id] || (inBase[it.Task.Id] && !inTheirs[it.Task.Id]) {
+			continue
+		}
+
+		ret = append(ret, it)
+	}
+
+	for _, it := range theirs {
+		if onBoard[it.Task.Id] || inOurs[it.Task.Id] || inBase[it.Task.Id] {
+			continue
+		}
+
+		ret = append(ret, it)
+	}
+
+	return ret
+}
+
+// indexTasks maps every task on the board, and the column it's in, by its ID.
+func indexTasks(board *Board) (map[string]*Task, map[string]string) {
+	tasks := map[string]*Task{}
+	columns := map[string]string{}
+
+	for columnId, columnTasks := range board.Tasks {
+		for _, task := range columnTasks {
+			tasks[task.Id] = task
+			columns[task.Id] = columnId
+		}
+	}
+
+	return tasks, columns
+}
+
+// tasksEqual compares tasks by their content rather than by their pointers.
+func tasksEqual(a *Task, b *Task) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// boardMetaJSON serializes everything on the board other than its tasks, other
+// than its archive and trash, and other than its task counter.
+func boardMetaJSON(board *Board) []byte {
+	meta := *board
+	meta.Tasks = nil
+	meta.Archive = nil
+	meta.Trash = nil
+	// The counter goes up on both sides as tasks are added, it's merged on its own.
+	meta.LastTaskNumber = 0
+
+	ret, _ := json.Marshal(meta)
+
+	return ret
+}
+
+// clone returns a deep copy of the board that shares no pointers with it.
+func (board *Board) clone() *Board {
+	content, err := json.Marshal(board)
+	if err != nil {
+		utils.SaveLog(utils.Error, "Failed to copy a board", map[string]any{"error": err.Error()})
+		return nil
+	}
+
+	ret := new(Board)
+	err = json.Unmarshal(content, ret)
+	if err != nil {
+		utils.SaveLog(utils.Error, "Failed to copy a board", map[string]any{"error": err.Error()})
+		return nil
+	}
+
+	return ret
+}
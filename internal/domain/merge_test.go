@@ -0,0 +1,277 @@
+package domain
+
+import (
+	"testing"
+)
+
+// openTestBoard returns the board with the given ID as read by the given config.
+func openTestBoard(t *testing.T, config *UserConfig, boardId string) *Board {
+	t.Helper()
+
+	boardOpt := config.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		t.Fatalf("Expected the board %s to be found", boardId)
+	}
+
+	return boardOpt.Unwrap()
+}
+
+// findTestTask returns the task with the given title on the board, and the column
+// it's in. It's nil if the board doesn't have it.
+func findTestTask(board *Board, title string) (*Task, *Column) {
+	for _, column := range board.Columns {
+		for _, task := range board.Tasks[column.Id] {
+			if task.Title == title {
+				return task, column
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// mustFindTestTask is findTestTask for tasks the test expects to be there.
+func mustFindTestTask(t *testing.T, board *Board, title string) (*Task, *Column) {
+	t.Helper()
+
+	task, column := findTestTask(board, title)
+	if task == nil {
+		t.Fatalf("Expected \"%s\" to be on the board", title)
+	}
+
+	return task, column
+}
+
+// mustSucceed fails the test if a change to the board failed.
+func mustSucceed(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("Failed to change the board. %s", err)
+	}
+}
+
+func TestMergeChangesOfTwoProcesses(t *testing.T) {
+	tests := []struct {
+		name string
+		// theirs is what another process changes and saves first.
+		theirs func(t *testing.T, config *UserConfig, board *Board)
+		// ours is what this process changes, on top of the same board, and saves last.
+		ours func(t *testing.T, config *UserConfig, board *Board)
+		// check looks at the board as saved after both.
+		check func(t *testing.T, board *Board)
+	}{
+		{
+			name: "both edit different tasks",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Description = "theirs" }))
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "two")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Description = "ours" }))
+			},
+			check: func(t *testing.T, board *Board) {
+				one, _ := mustFindTestTask(t, board, "one")
+				two, _ := mustFindTestTask(t, board, "two")
+				if one.Description != "theirs" || two.Description != "ours" {
+					t.Errorf("Expected both edits to be kept, got \"%s\" and \"%s\"", one.Description, two.Description)
+				}
+			},
+		},
+		{
+			name: "both edit the same task",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Description = "theirs" }))
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Description = "ours" }))
+			},
+			check: func(t *testing.T, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				if task.Description != "ours" {
+					t.Errorf("Expected the edit saved last to win, got \"%s\"", task.Description)
+				}
+				if len(task.Events) < 3 {
+					t.Errorf("Expected the history of both edits to be kept, got %d events", len(task.Events))
+				}
+			},
+		},
+		{
+			name: "one edits a task while the other moves it",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Description = "theirs" }))
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+			},
+			check: func(t *testing.T, board *Board) {
+				task, column := mustFindTestTask(t, board, "one")
+				if task.Description != "theirs" || column.Id != board.Columns[1].Id {
+					t.Errorf("Expected both the edit and the move to be kept, got \"%s\" in %s", task.Description, column.Name)
+				}
+			},
+		},
+		{
+			name: "one deletes a task while the other edits it",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.DeleteTask(board.Id, task))
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Description = "ours" }))
+			},
+			check: func(t *testing.T, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				if task.Description != "ours" {
+					t.Errorf("Expected the edit to be kept, got \"%s\"", task.Description)
+				}
+				if len(board.Trash) != 0 {
+					t.Errorf("Expected the edited task to be left out of the trash, got %d tasks in it", len(board.Trash))
+				}
+			},
+		},
+		{
+			name: "one edits a task while the other deletes it",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Description = "theirs" }))
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.DeleteTask(board.Id, task))
+			},
+			check: func(t *testing.T, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				if task.Description != "theirs" {
+					t.Errorf("Expected the edit to be kept, got \"%s\"", task.Description)
+				}
+				if len(board.Trash) != 0 {
+					t.Errorf("Expected the edited task to be left out of the trash, got %d tasks in it", len(board.Trash))
+				}
+			},
+		},
+		{
+			name: "one deletes a task the other didn't touch",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.DeleteTask(board.Id, task))
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "two")
+				mustSucceed(t, config.EditTask(board.Id, task, func(task *Task) { task.Description = "ours" }))
+			},
+			check: func(t *testing.T, board *Board) {
+				if task, _ := findTestTask(board, "one"); task != nil {
+					t.Errorf("Expected the deleted task to stay deleted")
+				}
+				if len(board.Trash) != 1 || board.Trash[0].Task.Title != "one" {
+					t.Errorf("Expected the deleted task to be in the trash, got %d tasks in it", len(board.Trash))
+				}
+			},
+		},
+		{
+			name: "both move the same task to different columns",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+			},
+			check: func(t *testing.T, board *Board) {
+				_, column := mustFindTestTask(t, board, "one")
+				if column.Id != board.Columns[1].Id {
+					t.Errorf("Expected the move saved last to win, got %s", column.Name)
+				}
+
+				count := 0
+				for _, task := range board.everyTask() {
+					if task.Title == "one" {
+						count++
+					}
+				}
+				if count != 1 {
+					t.Errorf("Expected the task to be on the board once, got %d", count)
+				}
+			},
+		},
+		{
+			name: "both add a task with the same number",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				addTestTask(t, config, board, "theirs")
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				addTestTask(t, config, board, "ours")
+			},
+			check: func(t *testing.T, board *Board) {
+				theirs, _ := mustFindTestTask(t, board, "theirs")
+				ours, _ := mustFindTestTask(t, board, "ours")
+				if key := board.GetTaskKey(theirs); key != "API-3" {
+					t.Errorf("Expected the task saved first to keep API-3, got %s", key)
+				}
+				if key := board.GetTaskKey(ours); key != "API-4" {
+					t.Errorf("Expected the task saved last to be renumbered to API-4, got %s", key)
+				}
+				if board.LastTaskNumber != 4 {
+					t.Errorf("Expected the last task number to be 4, got %d", board.LastTaskNumber)
+				}
+			},
+		},
+		{
+			name: "one deletes a column while the other moves a task to it",
+			theirs: func(t *testing.T, config *UserConfig, board *Board) {
+				err := config.DeleteColumn(board.Id, board.Columns[1].Id, "")
+				if err != nil {
+					t.Fatalf("Failed to delete the column. %s", err)
+				}
+			},
+			ours: func(t *testing.T, config *UserConfig, board *Board) {
+				task, _ := mustFindTestTask(t, board, "one")
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+			},
+			check: func(t *testing.T, board *Board) {
+				if len(board.Columns) != 2 {
+					t.Fatalf("Expected the column to stay deleted, got %d columns", len(board.Columns))
+				}
+
+				_, column := mustFindTestTask(t, board, "one")
+				if column.Id != board.GetBacklogColumn().Id {
+					t.Errorf("Expected the task to be moved to the backlog, got %s", column.Name)
+				}
+				for columnId := range board.Tasks {
+					columnOpt := board.GetColumnById(columnId)
+					if columnOpt.IsNone() {
+						t.Errorf("Expected no tasks to be left under the deleted column")
+					}
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, board := newTestConfig(t)
+			addTestTask(t, config, board, "one")
+			addTestTask(t, config, board, "two")
+
+			// Both processes read the board before either of them changes it.
+			theirConfig := reopenConfig(t, config)
+			ourConfig := reopenConfig(t, config)
+			theirBoard := openTestBoard(t, theirConfig, board.Id)
+			ourBoard := openTestBoard(t, ourConfig, board.Id)
+
+			test.theirs(t, theirConfig, theirBoard)
+			test.ours(t, ourConfig, ourBoard)
+
+			test.check(t, openTestBoard(t, reopenConfig(t, config), board.Id))
+		})
+	}
+}
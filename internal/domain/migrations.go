@@ -0,0 +1,423 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// CurrentSchemaVersion is the version of the persisted format this build reads and writes.
+// Bumping it means adding a migration to the registry below.
+const CurrentSchemaVersion = 8
+
+// document is the raw form of the config or of a board as it's persisted.
+// Migrations work on documents rather than on the domain types, since old
+// documents might not fit the types as they are now.
+type document = map[string]any
+
+// documentStore is a store that gives raw access to what it persists, so it can be migrated.
+type documentStore interface {
+	// loadConfigDocument returns the config with the boards index under "boards".
+	loadConfigDocument() (document, error)
+	saveConfigDocument(config document) error
+	loadBoardDocument(boardId string) (document, error)
+	saveBoardDocument(boardId string, board document) error
+	// backupAll keeps a copy of everything in the store, labeled with the given name.
+	backupAll(label string) error
+}
+
+// migration upgrades the persisted format by a single version.
+type migration struct {
+	// version is the schema version this migration upgrades to, from the one before it.
+	version     int
+	description string
+	// config upgrades the config document. It may go through the store to change
+	// other documents too. It's optional.
+	config func(store documentStore, config document) error
+	// board upgrades a single board document. It's optional.
+	board func(board document) error
+}
+
+// migrations is the registry of every migration, in order. Each one upgrades
+// from the version before it, so old files are upgraded one step at a time.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "Move every board out of config.json into its own file",
+		config:      migrateBoardsToOwnFiles,
+	},
+	{
+		version:     2,
+		description: "Backfill missing task fields and store created_at as RFC3339",
+		board:       migrateTasksCreatedAt,
+	},
+	{
+		version:     3,
+		description: "Give boards that share a name unique names",
+		config:      migrateUniqueBoardNames,
+	},
+	{
+		version:     4,
+		description: "Give every task a number for its key, like API-42",
+		board:       migrateTaskNumbers,
+	},
+	{
+		version:     5,
+		description: "Give every column an ID, and keep the tasks and the WIP limits by it",
+		board:       migrateColumnsToObjects,
+	},
+	{
+		version:     6,
+		description: "Give every column a role, going by where it is on the board",
+		board:       migrateColumnRoles,
+	},
+	{
+		version:     7,
+		description: "Backfill when every task was last updated, started and completed",
+		board:       migrateTaskTimestamps,
+	},
+	{
+		version:     8,
+		description: "Make sure the times of task events, comments and removals can be read",
+		board:       migrateRecordedTimes,
+	},
+}
+
+// migrateStore upgrades everything in the store to CurrentSchemaVersion, taking a
+// backup before every migration. The store should be locked.
+func migrateStore(store documentStore) error {
+	config, err := store.loadConfigDocument()
+	if err != nil {
+		return err
+	}
+
+	version := configSchemaVersion(config)
+	if version > CurrentSchemaVersion {
+		return fmt.Errorf(
+			"The config is at schema version %d, which is newer than this gotasks supports (%d). Please update gotasks",
+			version, CurrentSchemaVersion,
+		)
+	}
+
+	for _, it := range migrations {
+		if it.version <= version {
+			continue
+		}
+
+		utils.SaveLog(utils.Info, "Migrating the config", map[string]any{"version": it.version, "migration": it.description})
+
+		err = store.backupAll(fmt.Sprintf("schema-v%d", version))
+		if err != nil {
+			return fmt.Errorf("Failed to back up before migrating to schema version %d. %s", it.version, err)
+		}
+
+		if it.config != nil {
+			err = it.config(store, config)
+			if err != nil {
+				return fmt.Errorf("Failed to migrate to schema version %d. %s", it.version, err)
+			}
+		}
+
+		for _, boardId := range boardIdsOfConfigDocument(config) {
+			board, err := store.loadBoardDocument(boardId)
+			if err != nil {
+				// A broken board shouldn't keep every other board from being migrated.
+				// Every board has a schema version of its own, so the stores migrate it
+				// whenever it's read, see decodeBoard, and it's saved at the current
+				// version the next time it's saved.
+				utils.SaveLog(utils.Error, "Failed to read a board while migrating", map[string]any{"boardId": boardId, "error": err.Error()})
+				continue
+			}
+
+			if documentSchemaVersion(board) >= it.version {
+				continue
+			}
+
+			if it.board != nil {
+				err = it.board(board)
+				if err != nil {
+					return fmt.Errorf("Failed to migrate a board to schema version %d. %s", it.version, err)
+				}
+			}
+
+			board["schema_version"] = it.version
+
+			err = store.saveBoardDocument(boardId, board)
+			if err != nil {
+				return err
+			}
+		}
+
+		config["schema_version"] = it.version
+		version = it.version
+
+		err = store.saveConfigDocument(config)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateBoardDocument upgrades a single board document to CurrentSchemaVersion,
+// for boards that aren't migrated along with a config.
+func migrateBoardDocument(board document) error {
+	version := documentSchemaVersion(board)
+	if version > CurrentSchemaVersion {
+		return fmt.Errorf(
+			"The board is at schema version %d, which is newer than this gotasks supports (%d). Please update gotasks",
+			version, CurrentSchemaVersion,
+		)
+	}
+
+	for _, it := range migrations {
+		if it.version <= version {
+			continue
+		}
+
+		if it.board != nil {
+			err := it.board(board)
+			if err != nil {
+				return fmt.Errorf("Failed to migrate the board to schema version %d. %s", it.version, err)
+			}
+		}
+
+		board["schema_version"] = it.version
+	}
+
+	return nil
+}
+
+// decodeBoard reads a board as it's persisted. A board saved at an older schema
+// version, like one that couldn't be read while the store was migrated or one
+// restored from a backup, is migrated in memory first.
+func decodeBoard(content []byte) (*Board, error) {
+	version, err := persistedSchemaVersion(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != CurrentSchemaVersion {
+		doc := document{}
+		err = json.Unmarshal(content, &doc)
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeBoardDocument(doc)
+	}
+
+	board := new(Board)
+	err = json.Unmarshal(content, board)
+	if err != nil {
+		return nil, err
+	}
+
+	if board.Tasks == nil {
+		board.Tasks = map[string][]*Task{}
+	}
+
+	return board, nil
+}
+
+// persistedSchemaVersion returns the schema version the config or the board in the
+// given JSON was saved with, without reading the rest of it.
+func persistedSchemaVersion(content []byte) (int, error) {
+	header := struct {
+		SchemaVersion int `json:"schema_version"`
+	}{}
+
+	err := json.Unmarshal(content, &header)
+
+	return header.SchemaVersion, err
+}
+
+// decodeBoardDocument migrates the board document to CurrentSchemaVersion and reads it into a Board.
+func decodeBoardDocument(doc document) (*Board, error) {
+	err := migrateBoardDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	board := new(Board)
+	err = json.Unmarshal(content, board)
+	if err != nil {
+		return nil, err
+	}
+
+	if board.Tasks == nil {
+		board.Tasks = map[string][]*Task{}
+	}
+
+	return board, nil
+}
+
+// documentSchemaVersion returns the schema version a document was saved with.
+// Documents from before schema versions existed are version 0.
+func documentSchemaVersion(doc document) int {
+	version, ok := doc["schema_version"].(float64)
+	if !ok {
+		return 0
+	}
+
+	return int(version)
+}
+
+// configSchemaVersion is documentSchemaVersion for the config. Configs without a
+// version that already had their boards in their own files are version 1.
+func configSchemaVersion(config document) int {
+	if _, ok := config["schema_version"]; ok {
+		return documentSchemaVersion(config)
+	}
+
+	boards, _ := config["boards"].([]any)
+	for _, it := range boards {
+		entry, _ := it.(document)
+		if _, ok := entry["columns"]; ok {
+			return 0
+		}
+		if _, ok := entry["tasks"]; ok {
+			return 0
+		}
+	}
+
+	return 1
+}
+
+// boardIdsOfConfigDocument returns the ID of every board in the index of the config.
+func boardIdsOfConfigDocument(config document) []string {
+	ret := []string{}
+
+	boards, _ := config["boards"].([]any)
+	for _, it := range boards {
+		entry, _ := it.(document)
+		if boardId, ok := entry["id"].(string); ok && boardId != "" {
+			ret = append(ret, boardId)
+		}
+	}
+
+	return ret
+}
+
+// forEachTaskDocument calls the given function with every task of the board document.
+func forEachTaskDocument(board document, fn func(task document) error) error {
+	tasks, _ := board["tasks"].(document)
+	for _, column := range tasks {
+		columnTasks, _ := column.([]any)
+		for _, it := range columnTasks {
+			task, ok := it.(document)
+			if !ok {
+				continue
+			}
+
+			err := fn(task)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateBoardsToOwnFiles moves the boards that used to be stored whole inside
+// config.json to their own files, leaving only their entries in the index.
+func migrateBoardsToOwnFiles(store documentStore, config document) error {
+	boards, _ := config["boards"].([]any)
+	entries := []any{}
+
+	for _, it := range boards {
+		board, ok := it.(document)
+		if !ok {
+			continue
+		}
+
+		boardId, _ := board["id"].(string)
+		if boardId == "" {
+			boardId = uuid.New().String()
+			board["id"] = boardId
+		}
+
+		if _, ok := board["tasks"].(document); !ok {
+			board["tasks"] = document{}
+		}
+
+		// The board is saved at the version before the next migration, which then
+		// picks it up along with the rest.
+		board["schema_version"] = 1
+
+		err := store.saveBoardDocument(boardId, board)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, document{
+			"id":   boardId,
+			"name": board["name"],
+			"dir":  board["dir"],
+		})
+	}
+
+	config["boards"] = entries
+
+	return nil
+}
+
+// migrateTasksCreatedAt gives every task an ID and a created_at if it's missing
+// one, and moves created_at from the format of time.Time.String() to RFC3339.
+func migrateTasksCreatedAt(board document) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	return forEachTaskDocument(board, func(task document) error {
+		if taskId, _ := task["id"].(string); taskId == "" {
+			task["id"] = uuid.New().String()
+		}
+
+		createdAt, _ := task["created_at"].(string)
+		if createdAt == "" {
+			task["created_at"] = now
+			return nil
+		}
+
+		if _, err := time.Parse(time.RFC3339, createdAt); err == nil {
+			return nil
+		}
+
+		parsed, err := parseLegacyTime(createdAt)
+		if err != nil {
+			utils.SaveLog(utils.Warn, "Couldn't parse the created_at of a task, resetting it", map[string]any{"task": task["id"], "created_at": createdAt})
+			task["created_at"] = now
+			return nil
+		}
+
+		task["created_at"] = parsed.UTC().Format(time.RFC3339)
+
+		return nil
+	})
+}
+
+// parseLegacyTime reads a time persisted as RFC3339, or in the layout of
+// time.Time.String() that older versions wrote.
+func parseLegacyTime(text string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, text); err == nil {
+		return parsed, nil
+	}
+
+	// This is the layout time.Time.String() writes, minus the monotonic clock
+	// reading it adds for times that have one.
+	if i := strings.Index(text, " m="); i != -1 {
+		text = text[:i]
+	}
+
+	return time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", text)
+}
@@ -0,0 +1,534 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// newLegacyTestStore returns a memory store holding the given config.json as it was
+// written before schema versions existed.
+func newLegacyTestStore(configJSON string) *MemoryStore {
+	ret := NewMemoryStore()
+	ret.config = []byte(configJSON)
+
+	return ret
+}
+
+// findTestBoard returns the board with the given name in the config.
+func findTestBoard(t *testing.T, config *UserConfig, name string) *Board {
+	t.Helper()
+
+	boardOpt := config.GetBoard(name)
+	if boardOpt.IsNone() {
+		t.Fatalf("Expected a board named \"%s\", got %d boards", name, len(config.Boards))
+	}
+
+	return boardOpt.Unwrap()
+}
+
+func TestMigrateStore(t *testing.T) {
+	tests := []struct {
+		name string
+		// configJSON is the config.json the store starts with.
+		configJSON string
+		// check looks at the config as read after the migration.
+		check func(t *testing.T, config *UserConfig)
+	}{
+		{
+			name: "legacy single-file config",
+			configJSON: `{
+				"primary_color": 4,
+				"boards": [{
+					"name": "masa",
+					"dir": "/home/omar/masa",
+					"columns": ["Todo", "Open", "Closed"],
+					"tasks": {
+						"Todo": [
+							{"id": "todo-1", "title": "Lorem", "description": "Some optional Lorem", "created_at": "2024-01-01T10:00:00Z"},
+							{"title": "Without an ID"}
+						],
+						"Open": [{"id": "open-1", "title": "Ipsum", "created_at": "2024-01-02T10:00:00Z"}],
+						"Closed": [{"id": "closed-1", "title": "Dolor", "created_at": "2024-01-03T10:00:00Z"}]
+					}
+				}]
+			}`,
+			check: func(t *testing.T, config *UserConfig) {
+				if config.PrimaryColor != 4 {
+					t.Errorf("Expected the settings to be kept, got the color %d", config.PrimaryColor)
+				}
+
+				board := findTestBoard(t, config, "masa")
+				if board.Id == "" || board.Dir != "/home/omar/masa" {
+					t.Errorf("Expected the board to get an ID and keep its directory, got \"%s\" at %s", board.Id, board.Dir)
+				}
+
+				expectedColumns := []struct {
+					name   string
+					role   ColumnRole
+					titles []string
+				}{
+					{"Todo", BacklogRole, []string{"Lorem", "Without an ID"}},
+					{"Open", ActiveRole, []string{"Ipsum"}},
+					{"Closed", DoneRole, []string{"Dolor"}},
+				}
+				if len(board.Columns) != len(expectedColumns) {
+					t.Fatalf("Expected %d columns, got %d", len(expectedColumns), len(board.Columns))
+				}
+				for i, expected := range expectedColumns {
+					column := board.Columns[i]
+					if column.Id == "" || column.Name != expected.name || column.Role != expected.role {
+						t.Errorf("Expected the column %s to be %s, got %+v", expected.name, expected.role, column)
+					}
+
+					tasks := board.Tasks[column.Id]
+					if len(tasks) != len(expected.titles) {
+						t.Fatalf("Expected %s to have %d tasks, got %d", expected.name, len(expected.titles), len(tasks))
+					}
+					for j, task := range tasks {
+						if task.Title != expected.titles[j] || task.Id == "" || task.CreatedAt.IsZero() {
+							t.Errorf("Expected %s to be kept with an ID and a creation time, got %+v", expected.titles[j], task)
+						}
+					}
+				}
+
+				// The oldest task is the first one. The one without a creation time
+				// got the time of the migration, which makes it the newest.
+				expectedKeys := map[string]string{"Lorem": "MAS-1", "Ipsum": "MAS-2", "Dolor": "MAS-3", "Without an ID": "MAS-4"}
+				for title, expected := range expectedKeys {
+					task, _ := mustFindTestTask(t, board, title)
+					if key := board.GetTaskKey(task); key != expected {
+						t.Errorf("Expected %s to be %s, got %s", title, expected, key)
+					}
+				}
+
+				closed, _ := mustFindTestTask(t, board, "Dolor")
+				if closed.CompletedAt == nil || !closed.CompletedAt.Equal(closed.CreatedAt) {
+					t.Errorf("Expected a task in the done column to be completed since it was created, got %v", closed.CompletedAt)
+				}
+			},
+		},
+		{
+			name: "created_at written by time.Time.String()",
+			configJSON: `{
+				"boards": [{
+					"name": "api",
+					"dir": "/work/api",
+					"columns": ["Todo", "Done"],
+					"tasks": {
+						"Todo": [
+							{"id": "utc", "title": "UTC", "created_at": "2024-03-05 10:20:30.123456789 +0000 UTC"},
+							{"id": "monotonic", "title": "Monotonic", "created_at": "2024-03-04 08:00:00.5 +0000 UTC m=+0.001234567"},
+							{"id": "zone", "title": "Zone", "created_at": "2024-03-06 12:00:00 +0200 EET"},
+							{"id": "broken", "title": "Broken", "created_at": "yesterday"}
+						]
+					}
+				}]
+			}`,
+			check: func(t *testing.T, config *UserConfig) {
+				board := findTestBoard(t, config, "api")
+
+				expected := map[string]time.Time{
+					"UTC":       time.Date(2024, 3, 5, 10, 20, 30, 0, time.UTC),
+					"Monotonic": time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC),
+					"Zone":      time.Date(2024, 3, 6, 10, 0, 0, 0, time.UTC),
+				}
+				for title, createdAt := range expected {
+					task, _ := mustFindTestTask(t, board, title)
+					if !task.CreatedAt.Equal(createdAt) {
+						t.Errorf("Expected %s to be created at %s, got %s", title, createdAt, task.CreatedAt)
+					}
+				}
+
+				broken, _ := mustFindTestTask(t, board, "Broken")
+				if broken.CreatedAt.Before(time.Now().Add(-time.Hour)) {
+					t.Errorf("Expected a creation time that can't be read to be reset to the time of the migration, got %s", broken.CreatedAt)
+				}
+
+				// Numbered oldest first.
+				for title, expectedKey := range map[string]string{"Monotonic": "API-1", "UTC": "API-2", "Zone": "API-3", "Broken": "API-4"} {
+					task, _ := mustFindTestTask(t, board, title)
+					if key := board.GetTaskKey(task); key != expectedKey {
+						t.Errorf("Expected %s to be %s, got %s", title, expectedKey, key)
+					}
+				}
+			},
+		},
+		{
+			name: "a board with a single column",
+			configJSON: `{
+				"boards": [{
+					"name": "list",
+					"dir": "/work/list",
+					"columns": ["Todo"],
+					"tasks": {"Todo": [{"id": "1", "title": "Milk", "created_at": "2024-01-01T00:00:00Z"}]}
+				}]
+			}`,
+			check: func(t *testing.T, config *UserConfig) {
+				board := findTestBoard(t, config, "list")
+
+				if len(board.Columns) != 1 || board.Columns[0].Role != BacklogRole {
+					t.Fatalf("Expected the only column to be the backlog, got %+v", board.Columns)
+				}
+
+				task, _ := mustFindTestTask(t, board, "Milk")
+				if board.IsDone(task) || task.CompletedAt != nil {
+					t.Errorf("Expected the tasks of the only column not to be completed")
+				}
+			},
+		},
+		{
+			name: "tasks created at the same time",
+			configJSON: `{
+				"boards": [{
+					"name": "api",
+					"dir": "/work/api",
+					"columns": ["Todo", "Doing", "Done"],
+					"tasks": {
+						"Done": [{"id": "a", "title": "Done", "created_at": "2024-01-01T00:00:00Z"}],
+						"Doing": [
+							{"id": "c", "title": "Doing first", "created_at": "2024-01-01T00:00:00Z"},
+							{"id": "b", "title": "Doing second", "created_at": "2024-01-01T00:00:00Z"}
+						],
+						"Todo": [
+							{"id": "z", "title": "Todo", "created_at": "2024-01-01T00:00:00Z"},
+							{"id": "older", "title": "Older", "created_at": "2023-12-31T00:00:00Z"}
+						]
+					},
+					"trash": [{"task": {"id": "0", "title": "Trashed", "created_at": "2024-01-01T00:00:00Z"}, "column_id": "Todo"}]
+				}]
+			}`,
+			check: func(t *testing.T, config *UserConfig) {
+				board := findTestBoard(t, config, "api")
+
+				// Oldest first, then by column, by where they are in it and the trash last.
+				expectedKeys := map[string]string{
+					"Older":        "API-1",
+					"Todo":         "API-2",
+					"Doing first":  "API-3",
+					"Doing second": "API-4",
+					"Done":         "API-5",
+				}
+				for title, expected := range expectedKeys {
+					task, _ := mustFindTestTask(t, board, title)
+					if key := board.GetTaskKey(task); key != expected {
+						t.Errorf("Expected %s to be %s, got %s", title, expected, key)
+					}
+				}
+
+				if len(board.Trash) != 1 || board.GetTaskKey(board.Trash[0].Task) != "API-6" {
+					t.Errorf("Expected the task in the trash to be numbered last")
+				}
+			},
+		},
+		{
+			name: "duplicate and slash-containing board names",
+			configJSON: `{
+				"boards": [
+					{"name": "api", "dir": "/work/api", "columns": ["Todo"], "tasks": {"Todo": [{"id": "1", "title": "Work", "created_at": "2024-01-01T00:00:00Z"}]}},
+					{"name": "api", "dir": "/personal/api", "columns": ["Todo"], "tasks": {"Todo": [{"id": "2", "title": "Personal", "created_at": "2024-01-01T00:00:00Z"}]}},
+					{"name": "client/web", "dir": "/client/web", "columns": ["Todo"], "tasks": {}},
+					{"name": "personal/api", "dir": "/elsewhere/personal/api", "columns": ["Todo"], "tasks": {}}
+				]
+			}`,
+			check: func(t *testing.T, config *UserConfig) {
+				expected := []struct {
+					name   string
+					dir    string
+					prefix string
+				}{
+					{"api", "/work/api", "API"},
+					{"personal/api", "/personal/api", "API"},
+					{"client/web", "/client/web", "WEB"},
+					{"personal/personal/api", "/elsewhere/personal/api", "API"},
+				}
+
+				if len(config.Boards) != len(expected) {
+					t.Fatalf("Expected %d boards, got %d", len(expected), len(config.Boards))
+				}
+				for i, it := range expected {
+					entry := config.Boards[i]
+					if entry.Name != it.name || entry.Dir != it.dir {
+						t.Errorf("Expected board %d to be \"%s\" at %s, got \"%s\" at %s", i, it.name, it.dir, entry.Name, entry.Dir)
+						continue
+					}
+
+					board := findTestBoard(t, config, it.name)
+					if board.Name != it.name {
+						t.Errorf("Expected the board file to be renamed to \"%s\" along with the index, got \"%s\"", it.name, board.Name)
+					}
+					if board.TaskKeyPrefix != it.prefix {
+						t.Errorf("Expected \"%s\" to have the prefix %s, got %s", it.name, it.prefix, board.TaskKeyPrefix)
+					}
+				}
+
+				personal, _ := mustFindTestTask(t, findTestBoard(t, config, "personal/api"), "Personal")
+				if personal.Id != "2" {
+					t.Errorf("Expected the renamed board to keep its tasks")
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newLegacyTestStore(test.configJSON)
+
+			err := migrateStore(store)
+			if err != nil {
+				t.Fatalf("Failed to migrate. %s", err)
+			}
+
+			config, err := store.loadConfigDocument()
+			if err != nil {
+				t.Fatalf("Failed to read the migrated config. %s", err)
+			}
+			if version := documentSchemaVersion(config); version != CurrentSchemaVersion {
+				t.Errorf("Expected the config to be at schema version %d, got %d", CurrentSchemaVersion, version)
+			}
+
+			boardIds := boardIdsOfConfigDocument(config)
+			for _, boardId := range boardIds {
+				board, err := store.loadBoardDocument(boardId)
+				if err != nil {
+					t.Fatalf("Failed to read a migrated board. %s", err)
+				}
+				if version := documentSchemaVersion(board); version != CurrentSchemaVersion {
+					t.Errorf("Expected the board %s to be at schema version %d, got %d", board["name"], CurrentSchemaVersion, version)
+				}
+			}
+
+			// Running it again changes nothing.
+			configBefore := bytes.Clone(store.config)
+			boardsBefore := map[string][]byte{}
+			for _, boardId := range boardIds {
+				boardsBefore[boardId] = bytes.Clone(store.boards[boardId])
+			}
+
+			err = migrateStore(store)
+			if err != nil {
+				t.Fatalf("Failed to migrate again. %s", err)
+			}
+
+			if !bytes.Equal(store.config, configBefore) {
+				t.Errorf("Expected migrating again to leave the config as it was")
+			}
+			for _, boardId := range boardIds {
+				if !bytes.Equal(store.boards[boardId], boardsBefore[boardId]) {
+					t.Errorf("Expected migrating again to leave the board %s as it was", boardId)
+				}
+			}
+
+			userConfig, err := GetUserConfigFromStore(store)
+			if err != nil {
+				t.Fatalf("Failed to read the migrated config. %s", err)
+			}
+
+			test.check(t, userConfig)
+		})
+	}
+}
+
+func TestMigrateRecordedTimes(t *testing.T) {
+	// A board at the version before, edited by hand.
+	boardJSON := `{
+		"schema_version": 7,
+		"id": "board",
+		"name": "board",
+		"columns": [{"id": "todo", "name": "Todo", "role": "backlog"}],
+		"tasks": {
+			"todo": [{
+				"id": "1",
+				"title": "Edited",
+				"created_at": "2024-01-01T00:00:00Z",
+				"updated_at": "2024-01-01T00:00:00Z",
+				"events": [
+					{"kind": "created", "at": "2024-01-01T02:00:00+02:00"},
+					{"kind": "edited", "at": "2024-01-02 10:20:30.5 +0000 UTC m=+0.1"},
+					{"kind": "edited"}
+				],
+				"comments": [{"id": "c", "author": "omar", "text": "hi", "at": "soon"}]
+			}]
+		},
+		"trash": [
+			{"task": {"id": "2", "title": "Trashed", "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}, "column": "todo", "removed_at": "2024-01-03T00:00:00Z"},
+			{"task": {"id": "3", "title": "Broken", "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}, "column": "todo", "removed_at": ""}
+		]
+	}`
+
+	doc := document{}
+	err := json.Unmarshal([]byte(boardJSON), &doc)
+	if err != nil {
+		t.Fatalf("Failed to read the board. %s", err)
+	}
+
+	err = migrateBoardDocument(doc)
+	if err != nil {
+		t.Fatalf("Failed to migrate the board. %s", err)
+	}
+
+	migrated, _ := json.Marshal(doc)
+
+	// Running it again changes nothing.
+	err = migrateRecordedTimes(doc)
+	if err != nil {
+		t.Fatalf("Failed to migrate the board again. %s", err)
+	}
+	if again, _ := json.Marshal(doc); !bytes.Equal(again, migrated) {
+		t.Errorf("Expected migrating again to leave the board as it was")
+	}
+
+	board := new(Board)
+	err = json.Unmarshal(migrated, board)
+	if err != nil {
+		t.Fatalf("Expected the migrated board to be read, got %s", err)
+	}
+	if board.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("Expected the board to be at schema version %d, got %d", CurrentSchemaVersion, board.SchemaVersion)
+	}
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := board.Tasks["todo"][0]
+
+	// Times are kept to the second, like every other time of a task.
+	expectedEvents := []time.Time{createdAt, time.Date(2024, 1, 2, 10, 20, 30, 0, time.UTC), createdAt}
+	for i, expected := range expectedEvents {
+		if !task.Events[i].At.Equal(expected) {
+			t.Errorf("Expected event %d to be at %s, got %s", i, expected, task.Events[i].At)
+		}
+	}
+	if !task.Comments[0].At.Equal(createdAt) {
+		t.Errorf("Expected a comment at a time that can't be read to be at the creation of the task, got %s", task.Comments[0].At)
+	}
+
+	if !board.Trash[0].RemovedAt.Equal(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the time the task was trashed to be kept, got %s", board.Trash[0].RemovedAt)
+	}
+	if board.Trash[1].RemovedAt.Before(time.Now().Add(-time.Hour)) {
+		t.Errorf("Expected a time a task was trashed that can't be read to be the time of the migration, got %s", board.Trash[1].RemovedAt)
+	}
+}
+
+func TestLoadBoardAtOlderSchemaVersion(t *testing.T) {
+	stores := []struct {
+		name  string
+		store func(t *testing.T) Store
+	}{
+		{
+			name:  "memory",
+			store: func(t *testing.T) Store { return NewMemoryStore() },
+		},
+		{
+			name:  "json",
+			store: func(t *testing.T) Store { return NewJSONStore(t.TempDir()) },
+		},
+		{
+			name:  "sqlite",
+			store: func(t *testing.T) Store { return newTestSQLiteStore(t) },
+		},
+	}
+
+	// A board left at the version before columns had IDs, like one that couldn't be
+	// read while the rest of the store was migrated.
+	boardJSON := `{
+		"schema_version": 4,
+		"id": "board",
+		"name": "api",
+		"dir": "/work/api",
+		"columns": ["Todo", "Doing", "Done"],
+		"task_key_prefix": "API",
+		"last_task_number": 2,
+		"tasks": {
+			"Todo": [{"id": "1", "title": "Todo", "number": 1, "created_at": "2024-01-01T00:00:00Z"}],
+			"Done": [{"id": "2", "title": "Done", "number": 2, "created_at": "2024-01-02T00:00:00Z"}]
+		}
+	}`
+
+	for _, test := range stores {
+		t.Run(test.name, func(t *testing.T) {
+			store := test.store(t)
+			docStore := store.(documentStore)
+
+			board := document{}
+			err := json.Unmarshal([]byte(boardJSON), &board)
+			if err != nil {
+				t.Fatalf("Failed to read the board. %s", err)
+			}
+
+			// The config itself is migrated already.
+			err = docStore.saveConfigDocument(document{
+				"schema_version": CurrentSchemaVersion,
+				"boards":         []any{document{"id": "board", "name": "api", "dir": "/work/api"}},
+			})
+			if err != nil {
+				t.Fatalf("Failed to save the config. %s", err)
+			}
+			err = docStore.saveBoardDocument("board", board)
+			if err != nil {
+				t.Fatalf("Failed to save the board. %s", err)
+			}
+
+			config, err := GetUserConfigFromStore(store)
+			if err != nil {
+				t.Fatalf("Failed to read the config. %s", err)
+			}
+
+			loaded, err := config.LoadBoard(config.Boards[0])
+			if err != nil {
+				t.Fatalf("Expected the board to be migrated as it's read, got %s", err)
+			}
+
+			expectedColumns := []struct {
+				name  string
+				role  ColumnRole
+				title string
+			}{
+				{"Todo", BacklogRole, "Todo"},
+				{"Doing", ActiveRole, ""},
+				{"Done", DoneRole, "Done"},
+			}
+			if len(loaded.Columns) != len(expectedColumns) {
+				t.Fatalf("Expected %d columns, got %d", len(expectedColumns), len(loaded.Columns))
+			}
+			for i, expected := range expectedColumns {
+				column := loaded.Columns[i]
+				if column.Id == "" || column.Name != expected.name || column.Role != expected.role {
+					t.Errorf("Expected the column %s to be %s, got %+v", expected.name, expected.role, column)
+				}
+
+				tasks := loaded.Tasks[column.Id]
+				if expected.title == "" {
+					if len(tasks) != 0 {
+						t.Errorf("Expected %s to have no tasks, got %d", expected.name, len(tasks))
+					}
+					continue
+				}
+				if len(tasks) != 1 || tasks[0].Title != expected.title {
+					t.Errorf("Expected %s to keep its task", expected.name)
+				}
+			}
+
+			done, _ := mustFindTestTask(t, loaded, "Done")
+			if key := loaded.GetTaskKey(done); key != "API-2" || done.CompletedAt == nil {
+				t.Errorf("Expected the task to keep its key and be completed, got %s completed at %v", key, done.CompletedAt)
+			}
+
+			// It's saved at the current version along with the next change.
+			mustSucceed(t, config.EditTask(loaded.Id, done, func(task *Task) { task.Description = "migrated" }))
+
+			saved, err := docStore.loadBoardDocument("board")
+			if err != nil {
+				t.Fatalf("Failed to read the saved board. %s", err)
+			}
+			if version := documentSchemaVersion(saved); version != CurrentSchemaVersion {
+				t.Errorf("Expected the board to be saved at schema version %d, got %d", CurrentSchemaVersion, version)
+			}
+
+			reloaded := openTestBoard(t, reopenConfig(t, config), "board")
+			if task, _ := mustFindTestTask(t, reloaded, "Done"); task.Description != "migrated" || len(reloaded.Columns) != 3 {
+				t.Errorf("Expected the board to be read back as it was saved")
+			}
+		})
+	}
+}
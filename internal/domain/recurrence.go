@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// RecurrenceRule is how a recurring task comes back.
+type RecurrenceRule string
+
+const (
+	RecurDaily   RecurrenceRule = "daily"
+	RecurWeekly  RecurrenceRule = "weekly"
+	RecurMonthly RecurrenceRule = "monthly"
+	// RecurAfterDone brings the task back a number of days after it's done, rather
+	// than on a schedule.
+	RecurAfterDone RecurrenceRule = "after_done"
+)
+
+// TaskRecurrence is how often a task comes back. Every time it does, a fresh copy of
+// it, its next occurrence, is added to the backlog column.
+type TaskRecurrence struct {
+	Rule RecurrenceRule `json:"rule"`
+	// Days is how many days after the task is done it comes back, for RecurAfterDone.
+	Days int `json:"days,omitempty"`
+}
+
+// String writes the recurrence the way ParseRecurrence reads it, like "weekly" or
+// "every 3 days".
+func (recurrence *TaskRecurrence) String() string {
+	if recurrence == nil {
+		return ""
+	}
+
+	if recurrence.Rule == RecurAfterDone {
+		return fmt.Sprintf("every %d %s", recurrence.Days, utils.Cond(recurrence.Days == 1, "day", "days"))
+	}
+
+	return string(recurrence.Rule)
+}
+
+var recurAfterDonePattern = regexp.MustCompile(`^(?:every\s+)?(\d+)\s*(?:d|days?)$`)
+
+// ParseRecurrence reads "daily", "weekly" or "monthly", or a number of days after the
+// task is done like "every 3 days" or "3d". An empty text or "none" is no recurrence.
+func ParseRecurrence(text string) (*TaskRecurrence, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	switch text {
+	case "", "none":
+		return nil, nil
+	case string(RecurDaily), string(RecurWeekly), string(RecurMonthly):
+		return &TaskRecurrence{Rule: RecurrenceRule(text)}, nil
+	}
+
+	match := recurAfterDonePattern.FindStringSubmatch(text)
+	if match != nil {
+		days, err := strconv.Atoi(match[1])
+		if err == nil && days > 0 {
+			return &TaskRecurrence{Rule: RecurAfterDone, Days: days}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unknown recurrence \"%s\". It could be \"daily\", \"weekly\", \"monthly\" or \"every 3 days\" for 3 days after it's done", text)
+}
+
+// addInterval returns the date one interval of a scheduled recurrence after the given one.
+// A month after a day the next month doesn't have, like the 31st, is its last day.
+func (recurrence *TaskRecurrence) addInterval(date time.Time) time.Time {
+	switch recurrence.Rule {
+	case RecurDaily:
+		return date.AddDate(0, 0, 1)
+	case RecurWeekly:
+		return date.AddDate(0, 0, 7)
+	case RecurMonthly:
+		// The day 0 of the month after the next one is the last day of the next one.
+		lastDay := time.Date(date.Year(), date.Month() + 2, 0, 0, 0, 0, 0, date.Location()).Day()
+		return time.Date(date.Year(), date.Month() + 1, min(date.Day(), lastDay), date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+	}
+
+	return date.AddDate(0, 0, recurrence.Days)
+}
+
+// isScheduled reports if the task comes back on a schedule, whether it's done or not.
+func (recurrence *TaskRecurrence) isScheduled() bool {
+	return recurrence.Rule != RecurAfterDone
+}
+
+// getOccurrenceDate returns the day the occurrence of the task is for, which is its due
+// date, or the day it was created if it has none.
+func (task *Task) getOccurrenceDate() time.Time {
+	if date, err := time.ParseInLocation(DueDateLayout, task.DueDate, time.Local); err == nil {
+		return date
+	}
+
+	if !task.CreatedAt.IsZero() {
+		createdAt := task.CreatedAt.Local()
+		return time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, time.Local)
+	}
+
+	return today()
+}
+
+// getNextOccurrenceDate returns the day the next occurrence of the task is for. Scheduled
+// ones go one interval after this one, skipping the intervals that have already passed
+// save for the last of them, so a task that wasn't done for a while only comes back once.
+// The rest go a number of days after today.
+func (task *Task) getNextOccurrenceDate() time.Time {
+	if !task.Recurrence.isScheduled() {
+		return task.Recurrence.addInterval(today())
+	}
+
+	next := task.Recurrence.addInterval(task.getOccurrenceDate())
+	for !task.Recurrence.addInterval(next).After(today()) {
+		next = task.Recurrence.addInterval(next)
+	}
+
+	return next
+}
+
+// today returns the start of the current day.
+func today() time.Time {
+	now := time.Now()
+
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// shouldRecur reports if the task is due to come back: it's recurring, it didn't come
+// back already, and it's either done or the day of its next scheduled occurrence came.
+func (board *Board) shouldRecur(task *Task) bool {
+	if task.Recurrence == nil || task.NextOccurrenceId != "" {
+		return false
+	}
+
+	if board.IsDone(task) {
+		return true
+	}
+
+	return task.Recurrence.isScheduled() && !task.Recurrence.addInterval(task.getOccurrenceDate()).After(today())
+}
+
+// newNextOccurrence returns a fresh copy of the recurring task to add to the board as
+// its next occurrence. It has the same details and checklist, with nothing checked,
+// and is due on the day it's for.
+func (task *Task) newNextOccurrence() *Task {
+	ret := NewTask(task.Title, task.Description)
+
+	ret.Priority = task.Priority
+	ret.Labels = task.copy().Labels
+	ret.Assignee = task.Assignee
+	ret.Estimate = task.copy().Estimate
+	ret.Epic = task.Epic
+	ret.Recurrence = task.copy().Recurrence
+	ret.DueDate = task.getNextOccurrenceDate().Format(DueDateLayout)
+	ret.PreviousOccurrenceId = task.Id
+
+	for _, it := range task.Checklist {
+		ret.Checklist = append(ret.Checklist, newChecklistItem(it.Text))
+	}
+
+	return ret
+}
+
+// addNextOccurrence adds the next occurrence of the task on top of the backlog column,
+// linking the two in their histories.
+func (board *Board) addNextOccurrence(task *Task, next *Task) {
+	board.assignTaskNumber(next)
+
+	backlogColumn := board.GetBacklogColumn()
+	board.Tasks[backlogColumn.Id] = append(board.Tasks[backlogColumn.Id], next)
+
+	// The task recurring is added to its history going by this, like the rest of what
+	// happens to it. The next occurrence comes with its creation, saying what it's the
+	// next occurrence of.
+	task.NextOccurrenceId = next.Id
+
+	created := newTaskEvent(TaskCreated)
+	created.Occurrence = board.GetTaskKey(task)
+	next.Events = append(next.Events, created)
+}
+
+// GetOccurrenceKey returns the key of the occurrence of a recurring task with the given
+// ID, along with where it is, like in the archive once it was done. It returns an empty
+// key if the occurrence is gone for good.
+func (board *Board) GetOccurrenceKey(taskId string) (string, TaskShelf) {
+	if taskId == "" {
+		return "", OnBoard
+	}
+
+	taskOpt := board.GetTaskById(taskId)
+	if taskOpt.IsSome() {
+		return board.GetTaskKey(taskOpt.Unwrap()), OnBoard
+	}
+
+	removedTaskOpt, shelf := board.GetRemovedTaskById(taskId)
+	if removedTaskOpt.IsSome() {
+		return board.GetTaskKey(removedTaskOpt.Unwrap().Task), shelf
+	}
+
+	return "", OnBoard
+}
+
+// RecurDueTasks adds the next occurrence of every recurring task on the board that's
+// due to come back, like the ones that were done or whose next scheduled day came.
+// It's meant to be run when gotasks starts. It returns how many tasks came back.
+func (self *UserConfig) RecurDueTasks(board *Board) (int, error) {
+	if len(board.Columns) == 0 {
+		return 0, nil
+	}
+
+	tasks := []*Task{}
+	nexts := []*Task{}
+	taskIds := []string{}
+
+	for _, column := range board.Columns {
+		for _, task := range board.Tasks[column.Id] {
+			if !board.shouldRecur(task) {
+				continue
+			}
+
+			next := task.newNextOccurrence()
+			tasks = append(tasks, task)
+			nexts = append(nexts, next)
+			taskIds = append(taskIds, task.Id, next.Id)
+		}
+	}
+
+	if len(tasks) == 0 {
+		return 0, nil
+	}
+
+	description := fmt.Sprintf("Recur %d tasks", len(tasks))
+	if len(tasks) == 1 {
+		description = "Recur \"" + tasks[0].Title + "\""
+	}
+
+	err := self.runCommand(board, description, taskIds, func() error {
+		for i, task := range tasks {
+			board.addNextOccurrence(task, nexts[i])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(tasks), nil
+}
+
+// SetTaskRecurrence sets how often the task comes back, or stops it from coming back
+// if the recurrence is nil.
+func (self *UserConfig) SetTaskRecurrence(boardId string, task *Task, recurrence *TaskRecurrence) error {
+	if recurrence != nil && recurrence.Rule == RecurAfterDone && recurrence.Days <= 0 {
+		return errors.New("A task has to come back at least a day after it's done")
+	}
+
+	return self.EditTask(boardId, task, func(task *Task) {
+		task.Recurrence = recurrence
+	})
+}
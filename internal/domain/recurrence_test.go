@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// newRecurringTestTask returns a task recurring as given, due on the given date,
+// which is empty for a task without a due date.
+func newRecurringTestTask(t *testing.T, recurrence string, dueDate string) *Task {
+	t.Helper()
+
+	ret := NewTask("one", "")
+	ret.DueDate = dueDate
+
+	var err error
+	ret.Recurrence, err = ParseRecurrence(recurrence)
+	if err != nil {
+		t.Fatalf("Failed to read the recurrence. %s", err)
+	}
+
+	return ret
+}
+
+// testDate returns the start of the given day.
+func testDate(t *testing.T, date string) time.Time {
+	t.Helper()
+
+	ret, err := time.ParseInLocation(DueDateLayout, date, time.Local)
+	if err != nil {
+		t.Fatalf("Failed to read the date. %s", err)
+	}
+
+	return ret
+}
+
+func TestGetNextOccurrenceDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence string
+		dueDate    string
+		// want is the day the next occurrence is for. Dates far from now are used so
+		// it doesn't depend on the day the test runs, other than for the cases that
+		// are about today.
+		want func(t *testing.T) time.Time
+	}{
+		{
+			name:       "daily",
+			recurrence: "daily",
+			dueDate:    "2099-03-14",
+			want:       func(t *testing.T) time.Time { return testDate(t, "2099-03-15") },
+		},
+		{
+			name:       "weekly across a month",
+			recurrence: "weekly",
+			dueDate:    "2099-03-28",
+			want:       func(t *testing.T) time.Time { return testDate(t, "2099-04-04") },
+		},
+		{
+			name:       "monthly",
+			recurrence: "monthly",
+			dueDate:    "2099-03-14",
+			want:       func(t *testing.T) time.Time { return testDate(t, "2099-04-14") },
+		},
+		{
+			name:       "monthly on the 31st into a month of 30 days",
+			recurrence: "monthly",
+			dueDate:    "2099-03-31",
+			want:       func(t *testing.T) time.Time { return testDate(t, "2099-04-30") },
+		},
+		{
+			name:       "monthly on the 31st into February",
+			recurrence: "monthly",
+			dueDate:    "2099-01-31",
+			want:       func(t *testing.T) time.Time { return testDate(t, "2099-02-28") },
+		},
+		{
+			name:       "monthly on the 31st into February of a leap year",
+			recurrence: "monthly",
+			dueDate:    "2096-01-31",
+			want:       func(t *testing.T) time.Time { return testDate(t, "2096-02-29") },
+		},
+		{
+			name:       "monthly on February 29th",
+			recurrence: "monthly",
+			dueDate:    "2096-02-29",
+			want:       func(t *testing.T) time.Time { return testDate(t, "2096-03-29") },
+		},
+		{
+			name:       "monthly on December 31st into the next year",
+			recurrence: "monthly",
+			dueDate:    "2099-12-31",
+			want:       func(t *testing.T) time.Time { return testDate(t, "2100-01-31") },
+		},
+		{
+			name:       "a daily task that wasn't done for a while comes back for today",
+			recurrence: "daily",
+			dueDate:    "2000-01-01",
+			want:       func(t *testing.T) time.Time { return today() },
+		},
+		{
+			name:       "a number of days after it's done goes by today",
+			recurrence: "every 3 days",
+			dueDate:    "2099-03-14",
+			want:       func(t *testing.T) time.Time { return today().AddDate(0, 0, 3) },
+		},
+		{
+			name:       "a task without a due date goes by the day it was created",
+			recurrence: "weekly",
+			want:       func(t *testing.T) time.Time { return today().AddDate(0, 0, 7) },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := newRecurringTestTask(t, test.recurrence, test.dueDate)
+
+			got := task.getNextOccurrenceDate()
+			if want := test.want(t); !got.Equal(want) {
+				t.Errorf("Expected the next occurrence to be for %s, got %s", want.Format(DueDateLayout), got.Format(DueDateLayout))
+			}
+		})
+	}
+}
+
+func TestGetNextOccurrenceDateOfAWeeklyTaskThatWasNotDone(t *testing.T) {
+	task := newRecurringTestTask(t, "weekly", "2000-01-03")
+
+	// It comes back only once, on the last day it should have, and on the same day
+	// of the week.
+	got := task.getNextOccurrenceDate()
+	if got.After(today()) || !got.After(today().AddDate(0, 0, -7)) {
+		t.Errorf("Expected the next occurrence to be within the last week, got %s", got.Format(DueDateLayout))
+	}
+	if got.Weekday() != time.Monday {
+		t.Errorf("Expected the next occurrence to be on a Monday, got %s", got.Weekday())
+	}
+}
+
+func TestShouldRecur(t *testing.T) {
+	yesterday := today().AddDate(0, 0, -1).Format(DueDateLayout)
+	todayDate := today().Format(DueDateLayout)
+
+	tests := []struct {
+		name       string
+		recurrence string
+		dueDate    string
+		isDone     bool
+		// hasRecurred is set if the next occurrence of the task was added already.
+		hasRecurred bool
+		want        bool
+	}{
+		{
+			name:   "a task that doesn't recur",
+			isDone: true,
+			want:   false,
+		},
+		{
+			name:       "a done task",
+			recurrence: "every 3 days",
+			isDone:     true,
+			want:       true,
+		},
+		{
+			name:        "a done task that came back already",
+			recurrence:  "daily",
+			isDone:      true,
+			hasRecurred: true,
+			want:        false,
+		},
+		{
+			name:       "a task recurring after it's done that isn't done",
+			recurrence: "every 1 day",
+			dueDate:    yesterday,
+			want:       false,
+		},
+		{
+			name:       "a daily task whose next day came",
+			recurrence: "daily",
+			dueDate:    yesterday,
+			want:       true,
+		},
+		{
+			name:       "a daily task whose next day didn't come yet",
+			recurrence: "daily",
+			dueDate:    todayDate,
+			want:       false,
+		},
+		{
+			name:       "a monthly task on the 31st whose next month didn't come yet",
+			recurrence: "monthly",
+			dueDate:    "2099-01-31",
+			want:       false,
+		},
+		{
+			name:       "a monthly task from long ago",
+			recurrence: "monthly",
+			dueDate:    "2000-01-31",
+			want:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			board := newTestBoard("api")
+			task := newRecurringTestTask(t, test.recurrence, test.dueDate)
+			if test.hasRecurred {
+				task.NextOccurrenceId = "next"
+			}
+
+			column := board.Columns[0]
+			if test.isDone {
+				column = board.Columns[2]
+			}
+			board.Tasks[column.Id] = append(board.Tasks[column.Id], task)
+
+			if got := board.shouldRecur(task); got != test.want {
+				t.Errorf("Expected the task to recur to be %v, got %v", test.want, got)
+			}
+		})
+	}
+}
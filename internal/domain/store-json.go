@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// JSONStore keeps the global settings and the boards index in config.json and
+// every board in its own file under the boards folder. Files are replaced
+// atomically and their previous versions are kept under the backups folder.
+// The undo history of every board is kept under the history folder.
+type JSONStore struct {
+	configFilePath string
+	boardsDirPath  string
+	backupsDirPath string
+	historyDirPath string
+	lockFilePath   string
+}
+
+// NewJSONStore returns a store that lives in the given config folder.
+func NewJSONStore(configDirPath string) *JSONStore {
+	ret := new(JSONStore)
+
+	ret.configFilePath = filepath.Join(configDirPath, "config.json")
+	ret.boardsDirPath = filepath.Join(configDirPath, "boards")
+	ret.backupsDirPath = filepath.Join(configDirPath, "backups")
+	ret.historyDirPath = filepath.Join(configDirPath, "history")
+	ret.lockFilePath = filepath.Join(configDirPath, "gotasks.lock")
+
+	return ret
+}
+
+func (self *JSONStore) Exists() (bool, error) {
+	if _, err := os.Stat(self.configFilePath); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (self *JSONStore) LoadConfig() (*UserConfig, error) {
+	fileContent, err := os.ReadFile(self.configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	userConfig := NewDefaultUserConfig()
+	err = json.Unmarshal(fileContent, userConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return userConfig, nil
+}
+
+func (self *JSONStore) SaveConfig(config *UserConfig) error {
+	err := os.MkdirAll(filepath.Dir(self.configFilePath), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Failed to create the parent config folder. %s", err)
+	}
+
+	fileContent, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal user config. %s", err)
+	}
+
+	err = self.writeFile(self.configFilePath, fileContent)
+	if err != nil {
+		return fmt.Errorf("Failed to write to disk. %s", err)
+	}
+
+	return nil
+}
+
+func (self *JSONStore) LoadBoard(boardId string) (*Board, error) {
+	fileContent, err := os.ReadFile(self.getBoardFilePath(boardId))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBoard(fileContent)
+}
+
+func (self *JSONStore) SaveBoard(board *Board) error {
+	err := os.MkdirAll(self.boardsDirPath, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Failed to create the boards folder. %s", err)
+	}
+
+	fileContent, err := json.MarshalIndent(board, "", "\t")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal the board. %s", err)
+	}
+
+	err = self.writeFile(self.getBoardFilePath(board.Id), fileContent)
+	if err != nil {
+		return fmt.Errorf("Failed to write the board to disk. %s", err)
+	}
+
+	return nil
+}
+
+func (self *JSONStore) Revision(boardId string) (string, error) {
+	configInfo, err := os.Stat(self.configFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	boardInfo, err := os.Stat(self.getBoardFilePath(boardId))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"%d-%d:%d-%d",
+		configInfo.ModTime().UnixNano(), configInfo.Size(),
+		boardInfo.ModTime().UnixNano(), boardInfo.Size(),
+	), nil
+}
+
+func (self *JSONStore) LoadHistory(boardId string) (*History, error) {
+	fileContent, err := os.ReadFile(filepath.Join(self.historyDirPath, boardId+".json"))
+	if os.IsNotExist(err) {
+		return NewHistory(boardId), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	history := NewHistory(boardId)
+	err = json.Unmarshal(fileContent, history)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// SaveHistory writes the history without keeping a backup of it, unlike the
+// config and the boards.
+func (self *JSONStore) SaveHistory(history *History) error {
+	err := os.MkdirAll(self.historyDirPath, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Failed to create the history folder. %s", err)
+	}
+
+	fileContent, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal the history. %s", err)
+	}
+
+	return utils.WriteFileAtomic(filepath.Join(self.historyDirPath, history.BoardId+".json"), fileContent, 0644)
+}
+
+func (self *JSONStore) Lock() (func(), error) {
+	err := os.MkdirAll(filepath.Dir(self.lockFilePath), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return lockFile(self.lockFilePath)
+}
+
+func (self *JSONStore) loadConfigDocument() (document, error) {
+	return readJSONDocument(self.configFilePath)
+}
+
+func (self *JSONStore) saveConfigDocument(config document) error {
+	fileContent, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal user config. %s", err)
+	}
+
+	return self.writeFile(self.configFilePath, fileContent)
+}
+
+func (self *JSONStore) loadBoardDocument(boardId string) (document, error) {
+	return readJSONDocument(self.getBoardFilePath(boardId))
+}
+
+func (self *JSONStore) saveBoardDocument(boardId string, board document) error {
+	err := os.MkdirAll(self.boardsDirPath, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("Failed to create the boards folder. %s", err)
+	}
+
+	fileContent, err := json.MarshalIndent(board, "", "\t")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal the board. %s", err)
+	}
+
+	return self.writeFile(self.getBoardFilePath(boardId), fileContent)
+}
+
+// getBoardFilePath returns the path of the file the board with the given ID is stored in.
+func (self *JSONStore) getBoardFilePath(boardId string) string {
+	return filepath.Join(self.boardsDirPath, boardId + ".json")
+}
+
+// readJSONDocument reads the JSON file at the given path without mapping it to any type.
+func readJSONDocument(filePath string) (document, error) {
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := document{}
+	err = json.Unmarshal(fileContent, &ret)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
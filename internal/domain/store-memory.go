@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// MemoryStore keeps everything in memory and forgets it when the process exits.
+// Values are kept serialized so what's read back never shares pointers with
+// what was saved, the same way a store on disk behaves.
+type MemoryStore struct {
+	mutex sync.Mutex
+	// lockMutex is what Lock takes. It's separate from mutex, which every
+	// other method takes on its own.
+	lockMutex sync.Mutex
+	config    []byte
+	boards    map[string][]byte
+	histories map[string][]byte
+	// saves counts every save so it can be used as the revision.
+	saves int
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	ret := new(MemoryStore)
+
+	ret.boards = map[string][]byte{}
+	ret.histories = map[string][]byte{}
+
+	return ret
+}
+
+func (self *MemoryStore) Exists() (bool, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.config != nil, nil
+}
+
+func (self *MemoryStore) LoadConfig() (*UserConfig, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.config == nil {
+		return nil, fmt.Errorf("No user config was saved to the memory store")
+	}
+
+	userConfig := NewDefaultUserConfig()
+	err := json.Unmarshal(self.config, userConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return userConfig, nil
+}
+
+func (self *MemoryStore) SaveConfig(config *UserConfig) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	content, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal user config. %s", err)
+	}
+
+	self.config = content
+	self.saves += 1
+
+	return nil
+}
+
+func (self *MemoryStore) LoadBoard(boardId string) (*Board, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	content, ok := self.boards[boardId]
+	if !ok {
+		return nil, fmt.Errorf("No board with the ID \"%s\" in the memory store", boardId)
+	}
+
+	return decodeBoard(content)
+}
+
+func (self *MemoryStore) SaveBoard(board *Board) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	content, err := json.Marshal(board)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal the board. %s", err)
+	}
+
+	self.boards[board.Id] = content
+	self.saves += 1
+
+	return nil
+}
+
+func (self *MemoryStore) Revision(boardId string) (string, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return fmt.Sprint(self.saves), nil
+}
+
+func (self *MemoryStore) LoadHistory(boardId string) (*History, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	history := NewHistory(boardId)
+
+	content, ok := self.histories[boardId]
+	if !ok {
+		return history, nil
+	}
+
+	err := json.Unmarshal(content, history)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (self *MemoryStore) SaveHistory(history *History) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	content, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal the history. %s", err)
+	}
+
+	self.histories[history.BoardId] = content
+
+	return nil
+}
+
+func (self *MemoryStore) Lock() (func(), error) {
+	self.lockMutex.Lock()
+
+	return self.lockMutex.Unlock, nil
+}
+
+func (self *MemoryStore) loadConfigDocument() (document, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.config == nil {
+		return nil, fmt.Errorf("No user config was saved to the memory store")
+	}
+
+	ret := document{}
+	err := json.Unmarshal(self.config, &ret)
+
+	return ret, err
+}
+
+func (self *MemoryStore) saveConfigDocument(config document) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	content, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	self.config = content
+	self.saves += 1
+
+	return nil
+}
+
+func (self *MemoryStore) loadBoardDocument(boardId string) (document, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	content, ok := self.boards[boardId]
+	if !ok {
+		return nil, fmt.Errorf("No board with the ID \"%s\" in the memory store", boardId)
+	}
+
+	ret := document{}
+	err := json.Unmarshal(content, &ret)
+
+	return ret, err
+}
+
+func (self *MemoryStore) saveBoardDocument(boardId string, board document) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	content, err := json.Marshal(board)
+	if err != nil {
+		return err
+	}
+
+	self.boards[boardId] = content
+	self.saves += 1
+
+	return nil
+}
+
+// backupAll does nothing, there's nothing to recover in a store that doesn't outlive the process.
+func (self *MemoryStore) backupAll(label string) error {
+	return nil
+}
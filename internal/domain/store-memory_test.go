@@ -0,0 +1,310 @@
+package domain
+
+import (
+	"testing"
+)
+
+// newTestConfig returns a config kept in a new memory store, with a board named
+// "api" on it. Tests go through it so they never touch the config folder.
+func newTestConfig(t *testing.T) (*UserConfig, *Board) {
+	t.Helper()
+
+	config, err := SetupUserConfigInStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to set up the config. %s", err)
+	}
+
+	board, err := config.CreateBoard("api", "/projects/api")
+	if err != nil {
+		t.Fatalf("Failed to create the board. %s", err)
+	}
+
+	return config, board
+}
+
+// newTestBoard returns a board with the default columns and the given tasks in its
+// backlog, without saving it anywhere.
+func newTestBoard(id string, titles ...string) *Board {
+	ret := new(Board)
+	ret.SchemaVersion = CurrentSchemaVersion
+	ret.Id = id
+	ret.Name = id
+	ret.Columns = defaultColumns()
+	ret.Tasks = map[string][]*Task{}
+
+	for _, title := range titles {
+		columnId := ret.Columns[0].Id
+		ret.Tasks[columnId] = append(ret.Tasks[columnId], NewTask(title, ""))
+	}
+
+	return ret
+}
+
+// reopenConfig reads the config again from the store of the given one, the way
+// another process would.
+func reopenConfig(t *testing.T, config *UserConfig) *UserConfig {
+	t.Helper()
+
+	ret, err := GetUserConfigFromStore(config.store)
+	if err != nil {
+		t.Fatalf("Failed to read the config again. %s", err)
+	}
+
+	return ret
+}
+
+// addTestTask adds a task with the given title to the backlog of the board.
+func addTestTask(t *testing.T, config *UserConfig, board *Board, title string) *Task {
+	t.Helper()
+
+	task := NewTask(title, "")
+	err := config.AddTask(board.Id, task)
+	if err != nil {
+		t.Fatalf("Failed to add \"%s\". %s", title, err)
+	}
+
+	return task
+}
+
+func TestMemoryStoreConfigRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func() *UserConfig
+	}{
+		{
+			name:   "default config",
+			config: NewDefaultUserConfig,
+		},
+		{
+			name: "config with boards and templates",
+			config: func() *UserConfig {
+				ret := NewDefaultUserConfig()
+				ret.PrimaryColor = 3
+				ret.Boards = []*BoardEntry{
+					{Id: "1", Name: "api", Dir: "/projects/api"},
+					{Id: "2", Name: "web", Dir: "/projects/web"},
+				}
+				ret.Templates = []*TaskTemplate{{Name: "bug", Title: "Bug: {title}", Labels: []string{"bug"}}}
+				return ret
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore()
+
+			exists, err := store.Exists()
+			if err != nil || exists {
+				t.Fatalf("Expected an empty store to have no config, got %v, %v", exists, err)
+			}
+
+			config := test.config()
+			err = store.SaveConfig(config)
+			if err != nil {
+				t.Fatalf("Failed to save the config. %s", err)
+			}
+
+			loaded, err := store.LoadConfig()
+			if err != nil {
+				t.Fatalf("Failed to load the config. %s", err)
+			}
+
+			if loaded.PrimaryColor != config.PrimaryColor || loaded.SchemaVersion != config.SchemaVersion {
+				t.Errorf("Expected the settings to be kept, got %+v", loaded)
+			}
+			if len(loaded.Boards) != len(config.Boards) {
+				t.Fatalf("Expected %d boards, got %d", len(config.Boards), len(loaded.Boards))
+			}
+			for i, entry := range config.Boards {
+				if *loaded.Boards[i] != *entry {
+					t.Errorf("Expected board %d to be %+v, got %+v", i, entry, loaded.Boards[i])
+				}
+			}
+			if len(loaded.Templates) != len(config.Templates) {
+				t.Errorf("Expected %d templates, got %d", len(config.Templates), len(loaded.Templates))
+			}
+
+			// What's read back doesn't share anything with what was saved.
+			if len(config.Boards) > 0 && loaded.Boards[0] == config.Boards[0] {
+				t.Errorf("Expected the loaded config not to share pointers with the saved one")
+			}
+		})
+	}
+}
+
+func TestMemoryStoreBoardRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		board func() *Board
+	}{
+		{
+			name: "empty board",
+			board: func() *Board {
+				ret := new(Board)
+				ret.SchemaVersion = CurrentSchemaVersion
+				ret.Id = "empty"
+				ret.Name = "empty"
+				ret.Columns = defaultColumns()
+				return ret
+			},
+		},
+		{
+			name: "board with tasks, archive and trash",
+			board: func() *Board {
+				ret := new(Board)
+				ret.SchemaVersion = CurrentSchemaVersion
+				ret.Id = "full"
+				ret.Name = "full"
+				ret.TaskKeyPrefix = "FULL"
+				ret.LastTaskNumber = 3
+				ret.Columns = defaultColumns()
+				ret.Tasks = map[string][]*Task{}
+
+				for i, title := range []string{"one", "two"} {
+					task := NewTask(title, "")
+					task.Number = i + 1
+					task.Labels = []string{"api"}
+					ret.Tasks[ret.Columns[i].Id] = []*Task{task}
+				}
+
+				archived := NewTask("three", "")
+				archived.Number = 3
+				ret.Archive = []*RemovedTask{newRemovedTask(archived, ret.Columns[2].Id)}
+
+				return ret
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			board := test.board()
+
+			_, err := store.LoadBoard(board.Id)
+			if err == nil {
+				t.Fatalf("Expected loading a board that wasn't saved to fail")
+			}
+
+			err = store.SaveBoard(board)
+			if err != nil {
+				t.Fatalf("Failed to save the board. %s", err)
+			}
+
+			loaded, err := store.LoadBoard(board.Id)
+			if err != nil {
+				t.Fatalf("Failed to load the board. %s", err)
+			}
+
+			if loaded.Tasks == nil {
+				t.Errorf("Expected a loaded board to always have its tasks map")
+			}
+			if string(boardMetaJSON(loaded)) != string(boardMetaJSON(board)) {
+				t.Errorf("Expected the board to be kept as it was\nsaved:  %s\nloaded: %s", boardMetaJSON(board), boardMetaJSON(loaded))
+			}
+
+			for columnId, tasks := range board.Tasks {
+				if len(loaded.Tasks[columnId]) != len(tasks) {
+					t.Fatalf("Expected %d tasks in %s, got %d", len(tasks), columnId, len(loaded.Tasks[columnId]))
+				}
+				for i, task := range tasks {
+					if !tasksEqual(loaded.Tasks[columnId][i], task) {
+						t.Errorf("Expected %s to be kept as it was", task.Title)
+					}
+					if loaded.Tasks[columnId][i] == task {
+						t.Errorf("Expected the loaded tasks not to share pointers with the saved ones")
+					}
+				}
+			}
+
+			if len(loaded.Archive) != len(board.Archive) {
+				t.Errorf("Expected %d archived tasks, got %d", len(board.Archive), len(loaded.Archive))
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRevision(t *testing.T) {
+	tests := []struct {
+		name string
+		save func(store *MemoryStore, board *Board) error
+		// changes is whether the revision of the board is expected to change.
+		changes bool
+	}{
+		{
+			name:    "saving the board",
+			save:    func(store *MemoryStore, board *Board) error { return store.SaveBoard(board) },
+			changes: true,
+		},
+		{
+			name:    "saving the config",
+			save:    func(store *MemoryStore, board *Board) error { return store.SaveConfig(NewDefaultUserConfig()) },
+			changes: true,
+		},
+		{
+			name:    "saving the history",
+			save:    func(store *MemoryStore, board *Board) error { return store.SaveHistory(NewHistory(board.Id)) },
+			changes: false,
+		},
+		{
+			name:    "saving nothing",
+			save:    func(store *MemoryStore, board *Board) error { return nil },
+			changes: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			board := new(Board)
+			board.Id = "board"
+
+			err := store.SaveBoard(board)
+			if err != nil {
+				t.Fatalf("Failed to save the board. %s", err)
+			}
+
+			before, err := store.Revision(board.Id)
+			if err != nil {
+				t.Fatalf("Failed to get the revision. %s", err)
+			}
+
+			err = test.save(store, board)
+			if err != nil {
+				t.Fatalf("Failed to save. %s", err)
+			}
+
+			after, err := store.Revision(board.Id)
+			if err != nil {
+				t.Fatalf("Failed to get the revision. %s", err)
+			}
+
+			if (before != after) != test.changes {
+				t.Errorf("Expected the revision to change: %v, went from %s to %s", test.changes, before, after)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreThroughUserConfig(t *testing.T) {
+	config, board := newTestConfig(t)
+	task := addTestTask(t, config, board, "Write the tests")
+
+	reopened := reopenConfig(t, config)
+
+	boardOpt := reopened.GetBoardById(board.Id)
+	if boardOpt.IsNone() {
+		t.Fatalf("Expected the board to be found by a config read from the same store")
+	}
+
+	reopenedBoard := boardOpt.Unwrap()
+	taskOpt := reopenedBoard.GetTaskById(task.Id)
+	if taskOpt.IsNone() {
+		t.Fatalf("Expected the task to be found on the board read again")
+	}
+
+	if key := reopenedBoard.GetTaskKey(taskOpt.Unwrap()); key != "API-1" {
+		t.Errorf("Expected the task to be API-1, got %s", key)
+	}
+}
@@ -0,0 +1,603 @@
+package domain
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore keeps everything in a single SQLite database. Tasks get a row each,
+// so saving or reading a large board doesn't go through one huge JSON document.
+type SQLiteStore struct {
+	db             *sql.DB
+	dbFilePath     string
+	lockFilePath   string
+	backupsDirPath string
+}
+
+const sqliteSchema = `
+-- revision goes up every time the row is saved. See Revision.
+CREATE TABLE IF NOT EXISTS settings (
+	key      TEXT PRIMARY KEY,
+	value    TEXT NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS boards (
+	id       TEXT PRIMARY KEY,
+	name     TEXT NOT NULL,
+	dir      TEXT NOT NULL,
+	position INTEGER NOT NULL,
+	data     TEXT NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 0
+);
+
+-- column_name holds the ID of the column the task is in. It held the name before
+-- columns had IDs.
+CREATE TABLE IF NOT EXISTS tasks (
+	board_id    TEXT NOT NULL REFERENCES boards(id),
+	id          TEXT NOT NULL,
+	column_name TEXT NOT NULL,
+	position    INTEGER NOT NULL,
+	data        TEXT NOT NULL,
+	PRIMARY KEY (board_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS history (
+	board_id TEXT PRIMARY KEY,
+	data     TEXT NOT NULL
+);
+`
+
+// NewSQLiteStore opens, or creates, the gotasks.db database in the given config folder.
+func NewSQLiteStore(configDirPath string) (*SQLiteStore, error) {
+	err := os.MkdirAll(configDirPath, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the parent config folder. %s", err)
+	}
+
+	dbFilePath := filepath.Join(configDirPath, "gotasks.db")
+
+	db, err := sql.Open("sqlite", dbFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the database. %s", err)
+	}
+
+	// A single connection keeps writes serialized.
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(sqliteSchema)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Failed to create the database tables. %s", err)
+	}
+
+	// Databases made before the rows had revisions get the column added.
+	for _, table := range []string{"settings", "boards"} {
+		err = addSQLiteColumnIfMissing(db, table, "revision", "INTEGER NOT NULL DEFAULT 0")
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("Failed to upgrade the database tables. %s", err)
+		}
+	}
+
+	ret := new(SQLiteStore)
+	ret.db = db
+	ret.dbFilePath = dbFilePath
+	ret.lockFilePath = filepath.Join(configDirPath, "gotasks.db.lock")
+	ret.backupsDirPath = filepath.Join(configDirPath, "backups")
+
+	return ret, nil
+}
+
+// addSQLiteColumnIfMissing adds the column to the table unless it already has it.
+func addSQLiteColumnIfMissing(db *sql.DB, table string, column string, definition string) error {
+	var count int
+
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+
+	return err
+}
+
+func (self *SQLiteStore) Exists() (bool, error) {
+	var count int
+
+	err := self.db.QueryRow(`SELECT COUNT(*) FROM settings WHERE key = 'config'`).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count != 0, nil
+}
+
+func (self *SQLiteStore) LoadConfig() (*UserConfig, error) {
+	var content string
+
+	err := self.db.QueryRow(`SELECT value FROM settings WHERE key = 'config'`).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("No user config was saved to the database")
+	} else if err != nil {
+		return nil, err
+	}
+
+	userConfig := NewDefaultUserConfig()
+	err = json.Unmarshal([]byte(content), userConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// The index is made out of the boards table rather than the settings.
+	userConfig.Boards = []*BoardEntry{}
+
+	rows, err := self.db.Query(`SELECT id, name, dir FROM boards ORDER BY position`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := new(BoardEntry)
+
+		err = rows.Scan(&entry.Id, &entry.Name, &entry.Dir)
+		if err != nil {
+			return nil, err
+		}
+
+		userConfig.Boards = append(userConfig.Boards, entry)
+	}
+
+	return userConfig, rows.Err()
+}
+
+func (self *SQLiteStore) SaveConfig(config *UserConfig) error {
+	settings := *config
+	settings.Boards = nil
+
+	content, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal user config. %s", err)
+	}
+
+	return self.saveSettingsAndIndex(content, config.Boards)
+}
+
+// saveSettingsAndIndex saves the serialized settings and the boards index in a single transaction.
+func (self *SQLiteStore) saveSettingsAndIndex(settings []byte, entries []*BoardEntry) error {
+	tx, err := self.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`INSERT INTO settings (key, value, revision) VALUES ('config', ?, 1)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, revision = settings.revision + 1`,
+		string(settings),
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to save the settings. %s", err)
+	}
+
+	for i, entry := range entries {
+		_, err = tx.Exec(
+			`INSERT INTO boards (id, name, dir, position, data) VALUES (?, ?, ?, ?, '{}')
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name, dir = excluded.dir, position = excluded.position`,
+			entry.Id, entry.Name, entry.Dir, i,
+		)
+		if err != nil {
+			return fmt.Errorf("Failed to save the boards index. %s", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (self *SQLiteStore) LoadBoard(boardId string) (*Board, error) {
+	var content string
+
+	err := self.db.QueryRow(`SELECT data FROM boards WHERE id = ?`, boardId).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("No board with the ID \"%s\" in the database", boardId)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// A board saved at an older schema version is read as a whole to be migrated,
+	// since its tasks are migrated along with it. See decodeBoard.
+	version, err := persistedSchemaVersion([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+
+	if version != CurrentSchemaVersion {
+		doc, err := self.loadBoardDocument(boardId)
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeBoardDocument(doc)
+	}
+
+	board := new(Board)
+	err = json.Unmarshal([]byte(content), board)
+	if err != nil {
+		return nil, err
+	}
+
+	board.Tasks = map[string][]*Task{}
+
+	rows, err := self.db.Query(
+		`SELECT column_name, data FROM tasks WHERE board_id = ? ORDER BY column_name, position`,
+		boardId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var columnId string
+		var taskContent string
+
+		err = rows.Scan(&columnId, &taskContent)
+		if err != nil {
+			return nil, err
+		}
+
+		task := new(Task)
+		err = json.Unmarshal([]byte(taskContent), task)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse a task. %s", err)
+		}
+
+		board.Tasks[columnId] = append(board.Tasks[columnId], task)
+	}
+
+	return board, rows.Err()
+}
+
+// Revision is made of the revisions of the config row and of the row of the board,
+// so saving another board leaves it the same.
+func (self *SQLiteStore) Revision(boardId string) (string, error) {
+	var configRevision int64
+	var boardRevision int64
+
+	err := self.db.QueryRow(`SELECT revision FROM settings WHERE key = 'config'`).Scan(&configRevision)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	err = self.db.QueryRow(`SELECT revision FROM boards WHERE id = ?`, boardId).Scan(&boardRevision)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d-%d", configRevision, boardRevision), nil
+}
+
+func (self *SQLiteStore) LoadHistory(boardId string) (*History, error) {
+	var content string
+
+	history := NewHistory(boardId)
+
+	err := self.db.QueryRow(`SELECT data FROM history WHERE board_id = ?`, boardId).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return history, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(content), history)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (self *SQLiteStore) SaveHistory(history *History) error {
+	content, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal the history. %s", err)
+	}
+
+	_, err = self.db.Exec(
+		`INSERT INTO history (board_id, data) VALUES (?, ?)
+		ON CONFLICT (board_id) DO UPDATE SET data = excluded.data`,
+		history.BoardId, string(content),
+	)
+
+	return err
+}
+
+// Lock is taken on a file next to the database. SQLite's own locks only last
+// for a transaction, while this one has to span reading and saving.
+func (self *SQLiteStore) Lock() (func(), error) {
+	return lockFile(self.lockFilePath)
+}
+
+func (self *SQLiteStore) SaveBoard(board *Board) error {
+	// The tasks get their own rows so they are left out of the board's data.
+	boardData := *board
+	boardData.Tasks = nil
+
+	content, err := json.Marshal(boardData)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal the board. %s", err)
+	}
+
+	tasks := map[string][]sqliteTaskRow{}
+	for columnId, columnTasks := range board.Tasks {
+		for _, task := range columnTasks {
+			taskContent, err := json.Marshal(task)
+			if err != nil {
+				return fmt.Errorf("Failed to marshal a task. %s", err)
+			}
+
+			tasks[columnId] = append(tasks[columnId], sqliteTaskRow{id: task.Id, data: taskContent})
+		}
+	}
+
+	return self.saveBoardRows(board.Id, board.Name, board.Dir, content, tasks)
+}
+
+// sqliteTaskRow is a serialized task waiting to be written to the tasks table.
+type sqliteTaskRow struct {
+	id   string
+	data []byte
+}
+
+// saveBoardRows saves the serialized board and its tasks in a single transaction. Only
+// the tasks that changed are written, and only the ones that are gone are deleted.
+func (self *SQLiteStore) saveBoardRows(boardId string, name string, dir string, data []byte, tasks map[string][]sqliteTaskRow) error {
+	tx, err := self.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`INSERT INTO boards (id, name, dir, position, data, revision)
+		VALUES (?, ?, ?, (SELECT COUNT(*) FROM boards), ?, 1)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, dir = excluded.dir, data = excluded.data, revision = boards.revision + 1`,
+		boardId, name, dir, string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to save the board. %s", err)
+	}
+
+	oldTaskIds, err := querySQLiteStrings(tx, `SELECT id FROM tasks WHERE board_id = ?`, boardId)
+	if err != nil {
+		return fmt.Errorf("Failed to read the old tasks of the board. %s", err)
+	}
+
+	taskIds := map[string]bool{}
+	for columnName, rows := range tasks {
+		for i, row := range rows {
+			taskIds[row.id] = true
+
+			_, err = tx.Exec(
+				`INSERT INTO tasks (board_id, id, column_name, position, data) VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (board_id, id) DO UPDATE SET
+					column_name = excluded.column_name, position = excluded.position, data = excluded.data
+				WHERE column_name IS NOT excluded.column_name OR position IS NOT excluded.position OR data IS NOT excluded.data`,
+				boardId, row.id, columnName, i, string(row.data),
+			)
+			if err != nil {
+				return fmt.Errorf("Failed to save a task. %s", err)
+			}
+		}
+	}
+
+	for _, taskId := range oldTaskIds {
+		if taskIds[taskId] {
+			continue
+		}
+
+		_, err = tx.Exec(`DELETE FROM tasks WHERE board_id = ? AND id = ?`, boardId, taskId)
+		if err != nil {
+			return fmt.Errorf("Failed to delete a task that's gone from the board. %s", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// querySQLiteStrings returns the single text column of every row the query returns.
+func querySQLiteStrings(tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := []string{}
+	for rows.Next() {
+		var it string
+
+		err = rows.Scan(&it)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, it)
+	}
+
+	return ret, rows.Err()
+}
+
+func (self *SQLiteStore) loadConfigDocument() (document, error) {
+	config, err := self.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var content string
+	err = self.db.QueryRow(`SELECT value FROM settings WHERE key = 'config'`).Scan(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := document{}
+	err = json.Unmarshal([]byte(content), &ret)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []any{}
+	for _, entry := range config.Boards {
+		entries = append(entries, document{"id": entry.Id, "name": entry.Name, "dir": entry.Dir})
+	}
+	ret["boards"] = entries
+
+	return ret, nil
+}
+
+func (self *SQLiteStore) saveConfigDocument(config document) error {
+	settings := document{}
+	for key, value := range config {
+		if key != "boards" {
+			settings[key] = value
+		}
+	}
+
+	content, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	// Go through JSON to read the index entries out of the document.
+	entriesContent, err := json.Marshal(config["boards"])
+	if err != nil {
+		return err
+	}
+
+	entries := []*BoardEntry{}
+	err = json.Unmarshal(entriesContent, &entries)
+	if err != nil {
+		return err
+	}
+
+	return self.saveSettingsAndIndex(content, entries)
+}
+
+func (self *SQLiteStore) loadBoardDocument(boardId string) (document, error) {
+	var content string
+
+	err := self.db.QueryRow(`SELECT data FROM boards WHERE id = ?`, boardId).Scan(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := document{}
+	err = json.Unmarshal([]byte(content), &ret)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := self.db.Query(
+		`SELECT column_name, data FROM tasks WHERE board_id = ? ORDER BY column_name, position`,
+		boardId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := document{}
+	for rows.Next() {
+		var columnName string
+		var taskContent string
+
+		err = rows.Scan(&columnName, &taskContent)
+		if err != nil {
+			return nil, err
+		}
+
+		task := document{}
+		err = json.Unmarshal([]byte(taskContent), &task)
+		if err != nil {
+			return nil, err
+		}
+
+		columnTasks, _ := tasks[columnName].([]any)
+		tasks[columnName] = append(columnTasks, task)
+	}
+	ret["tasks"] = tasks
+
+	return ret, rows.Err()
+}
+
+func (self *SQLiteStore) saveBoardDocument(boardId string, board document) error {
+	boardData := document{}
+	for key, value := range board {
+		if key != "tasks" {
+			boardData[key] = value
+		}
+	}
+
+	content, err := json.Marshal(boardData)
+	if err != nil {
+		return err
+	}
+
+	tasks := map[string][]sqliteTaskRow{}
+	boardTasks, _ := board["tasks"].(document)
+	for columnName, column := range boardTasks {
+		columnTasks, _ := column.([]any)
+		for _, it := range columnTasks {
+			task, _ := it.(document)
+			taskId, _ := task["id"].(string)
+
+			taskContent, err := json.Marshal(task)
+			if err != nil {
+				return err
+			}
+
+			tasks[columnName] = append(tasks[columnName], sqliteTaskRow{id: taskId, data: taskContent})
+		}
+	}
+
+	name, _ := board["name"].(string)
+	dir, _ := board["dir"].(string)
+
+	return self.saveBoardRows(boardId, name, dir, content, tasks)
+}
+
+// GetFilePath returns the path of the database file.
+func (self *SQLiteStore) GetFilePath() string {
+	return self.dbFilePath
+}
+
+// GetBackupsDirPath returns the folder the copies of the database taken by backupAll are kept in.
+func (self *SQLiteStore) GetBackupsDirPath() string {
+	return self.backupsDirPath
+}
+
+// backupAll copies the whole database to a file in the backups folder.
+func (self *SQLiteStore) backupAll(label string) error {
+	err := os.MkdirAll(self.backupsDirPath, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	backupPath := filepath.Join(
+		self.backupsDirPath,
+		label + "." + time.Now().UTC().Format(backupTimeLayout) + ".db",
+	)
+
+	_, err = self.db.Exec(`VACUUM INTO ?`, backupPath)
+
+	return err
+}
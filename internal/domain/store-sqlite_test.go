@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+
+	store, err := NewSQLiteStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open the database. %s", err)
+	}
+	t.Cleanup(func() { store.db.Close() })
+
+	return store
+}
+
+func TestSQLiteStoreSaveBoard(t *testing.T) {
+	tests := []struct {
+		name string
+		// change is done to the board after it's saved once, before it's saved again.
+		change func(board *Board)
+		// expected are the titles of the tasks of every column after the second save.
+		expected [][]string
+	}{
+		{
+			name:     "nothing changed",
+			change:   func(board *Board) {},
+			expected: [][]string{{"one", "two", "three"}, nil, nil},
+		},
+		{
+			name: "a task edited",
+			change: func(board *Board) {
+				board.Tasks[board.Columns[0].Id][1].Title = "edited"
+			},
+			expected: [][]string{{"one", "edited", "three"}, nil, nil},
+		},
+		{
+			name: "a task deleted",
+			change: func(board *Board) {
+				tasks := board.Tasks[board.Columns[0].Id]
+				board.Tasks[board.Columns[0].Id] = append(tasks[:1:1], tasks[2:]...)
+			},
+			expected: [][]string{{"one", "three"}, nil, nil},
+		},
+		{
+			name: "a task moved to another column",
+			change: func(board *Board) {
+				tasks := board.Tasks[board.Columns[0].Id]
+				board.Tasks[board.Columns[2].Id] = []*Task{tasks[0]}
+				board.Tasks[board.Columns[0].Id] = tasks[1:]
+			},
+			expected: [][]string{{"two", "three"}, nil, {"one"}},
+		},
+		{
+			name: "a task added and the order changed",
+			change: func(board *Board) {
+				tasks := board.Tasks[board.Columns[0].Id]
+				board.Tasks[board.Columns[0].Id] = []*Task{NewTask("four", ""), tasks[2], tasks[1], tasks[0]}
+			},
+			expected: [][]string{{"four", "three", "two", "one"}, nil, nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newTestSQLiteStore(t)
+			board := newTestBoard("board", "one", "two", "three")
+
+			err := store.SaveBoard(board)
+			if err != nil {
+				t.Fatalf("Failed to save the board. %s", err)
+			}
+
+			test.change(board)
+
+			err = store.SaveBoard(board)
+			if err != nil {
+				t.Fatalf("Failed to save the board again. %s", err)
+			}
+
+			loaded, err := store.LoadBoard(board.Id)
+			if err != nil {
+				t.Fatalf("Failed to load the board. %s", err)
+			}
+
+			for i, column := range loaded.Columns {
+				titles := []string{}
+				for _, task := range loaded.Tasks[column.Id] {
+					titles = append(titles, task.Title)
+				}
+
+				if len(titles) != len(test.expected[i]) {
+					t.Fatalf("Expected %s to have %v, got %v", column.Name, test.expected[i], titles)
+				}
+				for j := range titles {
+					if titles[j] != test.expected[i][j] {
+						t.Errorf("Expected %s to have %v, got %v", column.Name, test.expected[i], titles)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteStoreRevision(t *testing.T) {
+	tests := []struct {
+		name string
+		save func(store *SQLiteStore) error
+		// changes is whether the revision of the board named "watched" is expected to change.
+		changes bool
+	}{
+		{
+			name:    "saving the board",
+			save:    func(store *SQLiteStore) error { return store.SaveBoard(newTestBoard("watched", "one")) },
+			changes: true,
+		},
+		{
+			name:    "saving another board",
+			save:    func(store *SQLiteStore) error { return store.SaveBoard(newTestBoard("other", "one")) },
+			changes: false,
+		},
+		{
+			name:    "saving the config",
+			save:    func(store *SQLiteStore) error { return store.SaveConfig(NewDefaultUserConfig()) },
+			changes: true,
+		},
+		{
+			name:    "saving the history of the board",
+			save:    func(store *SQLiteStore) error { return store.SaveHistory(NewHistory("watched")) },
+			changes: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newTestSQLiteStore(t)
+
+			err := store.SaveConfig(NewDefaultUserConfig())
+			if err != nil {
+				t.Fatalf("Failed to save the config. %s", err)
+			}
+			for _, id := range []string{"watched", "other"} {
+				err = store.SaveBoard(newTestBoard(id))
+				if err != nil {
+					t.Fatalf("Failed to save the board. %s", err)
+				}
+			}
+
+			before, err := store.Revision("watched")
+			if err != nil {
+				t.Fatalf("Failed to get the revision. %s", err)
+			}
+
+			err = test.save(store)
+			if err != nil {
+				t.Fatalf("Failed to save. %s", err)
+			}
+
+			after, err := store.Revision("watched")
+			if err != nil {
+				t.Fatalf("Failed to get the revision. %s", err)
+			}
+
+			if (before != after) != test.changes {
+				t.Errorf("Expected the revision to change: %v, went from %s to %s", test.changes, before, after)
+			}
+		})
+	}
+}
+
+func TestSQLiteStoreUpgradesTablesWithoutRevisions(t *testing.T) {
+	dirPath := t.TempDir()
+
+	db, err := sql.Open("sqlite", filepath.Join(dirPath, "gotasks.db"))
+	if err != nil {
+		t.Fatalf("Failed to open the database. %s", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE settings (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+		CREATE TABLE boards (id TEXT PRIMARY KEY, name TEXT NOT NULL, dir TEXT NOT NULL, position INTEGER NOT NULL, data TEXT NOT NULL);
+		INSERT INTO boards (id, name, dir, position, data) VALUES ('old', 'old', '/old', 0, '{"id": "old", "name": "old"}');
+	`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create the old tables. %s", err)
+	}
+
+	store, err := NewSQLiteStore(dirPath)
+	if err != nil {
+		t.Fatalf("Failed to open a database made before revisions. %s", err)
+	}
+	defer store.db.Close()
+
+	before, err := store.Revision("old")
+	if err != nil {
+		t.Fatalf("Failed to get the revision. %s", err)
+	}
+
+	board, err := store.LoadBoard("old")
+	if err != nil {
+		t.Fatalf("Failed to load the old board. %s", err)
+	}
+
+	err = store.SaveBoard(board)
+	if err != nil {
+		t.Fatalf("Failed to save the old board. %s", err)
+	}
+
+	after, _ := store.Revision("old")
+	if before == after {
+		t.Errorf("Expected saving the old board to change its revision")
+	}
+}
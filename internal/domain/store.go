@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/okira-e/gotasks/internal/vars"
+)
+
+// Store is where the user config and the boards with their tasks are persisted.
+// UserConfig reads and writes only through it, so swapping the store swaps
+// where everything lives.
+type Store interface {
+	// Exists reports if a user config was saved to this store before.
+	Exists() (bool, error)
+	// LoadConfig reads the global settings and the boards index. It doesn't read the boards.
+	LoadConfig() (*UserConfig, error)
+	// SaveConfig writes the global settings and the boards index.
+	SaveConfig(config *UserConfig) error
+	// LoadBoard reads a single board with all of its tasks. A board saved at an older
+	// schema version is migrated as it's read.
+	LoadBoard(boardId string) (*Board, error)
+	// SaveBoard writes a single board with all of its tasks.
+	SaveBoard(board *Board) error
+	// Revision returns a token that changes whenever the config or the given board
+	// is saved, including by another process. Only equality between tokens means anything.
+	Revision(boardId string) (string, error)
+	// LoadHistory reads the undo history of a board. A board without one gets an empty history.
+	LoadHistory(boardId string) (*History, error)
+	// SaveHistory writes the undo history of a board.
+	SaveHistory(history *History) error
+	// Lock takes a lock on the store shared with every other process using it, so
+	// reading, changing and saving can happen without anyone saving in between.
+	// It returns the function that releases it.
+	Lock() (func(), error)
+}
+
+// storeBasedOnEnv is the store shared by the whole process once it was picked.
+var storeBasedOnEnv Store
+
+// GetStoreBasedOnEnv returns the store picked through the GOTASKS_STORE environment
+// variable. It defaults to the JSON files in the config folder.
+func GetStoreBasedOnEnv() (Store, error) {
+	if storeBasedOnEnv != nil {
+		return storeBasedOnEnv, nil
+	}
+	
+	store, err := newStoreBasedOnEnv()
+	if err != nil {
+		return nil, err
+	}
+	
+	storeBasedOnEnv = store
+	
+	return store, nil
+}
+
+func newStoreBasedOnEnv() (Store, error) {
+	configDirPath, err := GetConfigDirPathBasedOnOS()
+	if err != nil {
+		return nil, err
+	}
+
+	storeName := os.Getenv(vars.StoreFlag)
+
+	switch storeName {
+	case "", "json":
+		return NewJSONStore(configDirPath), nil
+
+	case "sqlite":
+		return NewSQLiteStore(configDirPath)
+
+	case "memory":
+		return NewMemoryStore(), nil
+
+	default:
+		return nil, fmt.Errorf("Unknown store \"%s\". It could be \"json\", \"sqlite\" or \"memory\"", storeName)
+	}
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SwimlaneGrouping is what the tasks of a board are split into horizontal lanes by,
+// crossing all of its columns. The empty grouping shows the board without lanes.
+type SwimlaneGrouping string
+
+const (
+	NoSwimlanes       SwimlaneGrouping = ""
+	LabelSwimlanes    SwimlaneGrouping = "label"
+	AssigneeSwimlanes SwimlaneGrouping = "assignee"
+	PrioritySwimlanes SwimlaneGrouping = "priority"
+	EpicSwimlanes     SwimlaneGrouping = "epic"
+)
+
+// SwimlaneGroupings are all the ways a board can be split into lanes, in the order
+// they're cycled through.
+var SwimlaneGroupings = []SwimlaneGrouping{NoSwimlanes, LabelSwimlanes, AssigneeSwimlanes, PrioritySwimlanes, EpicSwimlanes}
+
+// ParseSwimlaneGrouping reads a grouping like "label", or "none" for no lanes.
+func ParseSwimlaneGrouping(text string) (SwimlaneGrouping, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "none" {
+		return NoSwimlanes, nil
+	}
+
+	for _, it := range SwimlaneGroupings {
+		if it != NoSwimlanes && text == string(it) {
+			return it, nil
+		}
+	}
+
+	return NoSwimlanes, fmt.Errorf("Unknown swimlanes \"%s\". They could be \"label\", \"assignee\", \"priority\", \"epic\" or \"none\"", text)
+}
+
+// Swimlane is a horizontal lane of the board holding the tasks that share a label,
+// an assignee, a priority or an epic.
+type Swimlane struct {
+	// Key is what the tasks in the lane share, like the name of a label. It's empty
+	// for the lane of the tasks that have none.
+	Key  string
+	Name string
+}
+
+// GetSwimlaneKey returns the key of the lane the task goes in, going by the swimlanes
+// of the board. Tasks with more than one label go in the lane of their first one.
+func (board *Board) GetSwimlaneKey(task *Task) string {
+	switch board.Swimlanes {
+	case LabelSwimlanes:
+		if len(task.Labels) > 0 {
+			return task.Labels[0]
+		}
+	case AssigneeSwimlanes:
+		return task.Assignee
+	case PrioritySwimlanes:
+		return string(task.Priority)
+	case EpicSwimlanes:
+		return task.Epic
+	}
+
+	return ""
+}
+
+// GetSwimlanes returns the lanes the tasks on the board are in, going by its swimlanes.
+// Priorities go from the most urgent down and the rest are sorted by name, with the
+// lane of the tasks that have none last. It's empty if the board has no swimlanes.
+func (board *Board) GetSwimlanes() []*Swimlane {
+	if board.Swimlanes == NoSwimlanes {
+		return []*Swimlane{}
+	}
+
+	keys := []string{}
+	hasEmptyKey := false
+
+	for _, column := range board.Columns {
+		for _, task := range board.Tasks[column.Id] {
+			key := board.GetSwimlaneKey(task)
+			if key == "" {
+				hasEmptyKey = true
+			} else if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	if board.Swimlanes == PrioritySwimlanes {
+		priorities := []TaskPriority{PriorityUrgent, PriorityHigh, PriorityMedium, PriorityLow}
+		slices.SortFunc(keys, func(a string, b string) int {
+			return slices.Index(priorities, TaskPriority(a)) - slices.Index(priorities, TaskPriority(b))
+		})
+	} else {
+		slices.SortFunc(keys, func(a string, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		})
+	}
+
+	ret := []*Swimlane{}
+	for _, key := range keys {
+		ret = append(ret, &Swimlane{Key: key, Name: board.getSwimlaneName(key)})
+	}
+
+	if hasEmptyKey {
+		ret = append(ret, &Swimlane{Key: "", Name: board.getSwimlaneName("")})
+	}
+
+	return ret
+}
+
+// getSwimlaneName returns what the lane with the given key is called, like "#api",
+// "@omar" or "No label".
+func (board *Board) getSwimlaneName(key string) string {
+	if key == "" {
+		switch board.Swimlanes {
+		case LabelSwimlanes:
+			return "No label"
+		case AssigneeSwimlanes:
+			return "Unassigned"
+		case PrioritySwimlanes:
+			return "No priority"
+		case EpicSwimlanes:
+			return "No epic"
+		}
+
+		return ""
+	}
+
+	switch board.Swimlanes {
+	case LabelSwimlanes:
+		return "#" + key
+	case AssigneeSwimlanes:
+		return "@" + key
+	case PrioritySwimlanes:
+		return strings.ToUpper(key[:1]) + key[1:]
+	}
+
+	return key
+}
+
+// SetSwimlanes sets what the tasks of the board are split into lanes by.
+func (self *UserConfig) SetSwimlanes(boardId string, grouping SwimlaneGrouping) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to set its swimlanes")
+	}
+
+	board := boardOpt.Unwrap()
+	board.Swimlanes = grouping
+
+	return self.UpdateBoard(board)
+}
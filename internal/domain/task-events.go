@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TaskEventKind is what happened to a task in a TaskEvent.
+type TaskEventKind string
+
+const (
+	TaskCreated   TaskEventKind = "created"
+	TaskEdited    TaskEventKind = "edited"
+	TaskMoved     TaskEventKind = "moved"
+	TaskDeleted   TaskEventKind = "deleted"
+	TaskArchived  TaskEventKind = "archived"
+	TaskRestored  TaskEventKind = "restored"
+	TaskCommented TaskEventKind = "commented"
+	TaskRecurred  TaskEventKind = "recurred"
+	// TaskMovedToBoard and TaskCopiedToBoard are added to the task that was moved or
+	// copied to another board, and TaskMovedFromBoard to the task it became there.
+	TaskMovedToBoard   TaskEventKind = "moved_to_board"
+	TaskMovedFromBoard TaskEventKind = "moved_from_board"
+	TaskCopiedToBoard  TaskEventKind = "copied_to_board"
+)
+
+// TaskEvent is a single entry in the history of a task. Events are only ever
+// appended to a task, never changed or removed.
+type TaskEvent struct {
+	Kind TaskEventKind `json:"kind"`
+	At   time.Time     `json:"at"`
+	// FromColumn and ToColumn are set for TaskMoved.
+	FromColumn string `json:"from_column,omitempty"`
+	ToColumn   string `json:"to_column,omitempty"`
+	// Occurrence is the key of the next occurrence of the task for TaskRecurred, and
+	// of the previous one for the TaskCreated of a task that came back.
+	Occurrence string `json:"occurrence,omitempty"`
+	// Board and TaskKey are the name of the other board and the key of the task there,
+	// for the events of a task moved or copied to another board, and for the TaskCreated
+	// of a copy.
+	Board   string `json:"board,omitempty"`
+	TaskKey string `json:"task_key,omitempty"`
+}
+
+func newTaskEvent(kind TaskEventKind) *TaskEvent {
+	ret := new(TaskEvent)
+
+	ret.Kind = kind
+	ret.At = timestampNow()
+
+	return ret
+}
+
+// String describes the event in a line, like "Moved from Todo to Done".
+func (event *TaskEvent) String() string {
+	switch event.Kind {
+	case TaskMoved:
+		return fmt.Sprintf("Moved from %s to %s", event.FromColumn, event.ToColumn)
+	case TaskRecurred:
+		return "Recurred as " + event.Occurrence
+	case TaskMovedToBoard:
+		return fmt.Sprintf("Moved to the board %s as %s", event.Board, event.TaskKey)
+	case TaskMovedFromBoard:
+		return fmt.Sprintf("Moved from the board %s, where it was %s", event.Board, event.TaskKey)
+	case TaskCopiedToBoard:
+		return fmt.Sprintf("Copied to the board %s as %s", event.Board, event.TaskKey)
+	case TaskCreated:
+		if event.Occurrence != "" {
+			return "Created as the next occurrence of " + event.Occurrence
+		}
+		if event.Board != "" {
+			return fmt.Sprintf("Created as a copy of %s from the board %s", event.TaskKey, event.Board)
+		}
+
+		return "Created"
+	default:
+		kind := string(event.Kind)
+		if kind == "" {
+			return ""
+		}
+
+		return strings.ToUpper(kind[:1]) + kind[1:]
+	}
+}
+
+// GetEvents returns the history of the task, oldest first. Tasks from before events
+// were recorded get their creation added from CreatedAt.
+func (task *Task) GetEvents() []*TaskEvent {
+	if len(task.Events) > 0 && task.Events[0].Kind == TaskCreated {
+		return task.Events
+	}
+
+	created := &TaskEvent{
+		Kind: TaskCreated,
+		At:   task.CreatedAt,
+	}
+
+	return append([]*TaskEvent{created}, task.Events...)
+}
+
+// taskEventsBetween returns the events that take a task from one snapshot of it to
+// another. Either snapshot is nil if the task isn't on the board in it. A task coming
+// back through an undo or a redo is restored rather than created.
+func taskEventsBetween(from *TaskSnapshot, to *TaskSnapshot, isUndoOrRedo bool) []*TaskEvent {
+	if from == nil && to == nil {
+		return nil
+	}
+
+	if from == nil {
+		if isUndoOrRedo {
+			return []*TaskEvent{newTaskEvent(TaskRestored)}
+		}
+
+		// Tasks can come with their creation, like the next occurrence of a recurring task.
+		if len(to.Task.Events) > 0 {
+			return nil
+		}
+
+		return []*TaskEvent{newTaskEvent(TaskCreated)}
+	}
+
+	if to == nil {
+		if from.Shelf != OnBoard {
+			// Emptied out of the trash. There's no history left to add to.
+			return nil
+		}
+
+		// A task moved to another board has it in its history already.
+		if len(from.Task.Events) > 0 && from.Task.Events[len(from.Task.Events) - 1].Kind == TaskMovedToBoard {
+			return nil
+		}
+
+		return []*TaskEvent{newTaskEvent(TaskDeleted)}
+	}
+
+	ret := []*TaskEvent{}
+
+	if from.Shelf != to.Shelf {
+		switch to.Shelf {
+		case TrashShelf:
+			ret = append(ret, newTaskEvent(TaskDeleted))
+		case ArchiveShelf:
+			ret = append(ret, newTaskEvent(TaskArchived))
+		default:
+			ret = append(ret, newTaskEvent(TaskRestored))
+		}
+	}
+
+	// Comments are only taken away by undoing them, which counts as an edit.
+	if !taskContentEqual(from.Task, to.Task) || len(to.Task.Comments) < len(from.Task.Comments) {
+		ret = append(ret, newTaskEvent(TaskEdited))
+	}
+
+	if len(to.Task.Comments) > len(from.Task.Comments) {
+		ret = append(ret, newTaskEvent(TaskCommented))
+	}
+
+	// The column of a task in the archive or the trash is the one it was taken from.
+	// A task that's restored to another one, as its column is gone, isn't moved.
+	if from.Column != to.Column && from.Shelf == OnBoard && to.Shelf == OnBoard {
+		event := newTaskEvent(TaskMoved)
+		event.FromColumn = from.getColumnName()
+		event.ToColumn = to.getColumnName()
+
+		ret = append(ret, event)
+	}
+
+	if from.Task.NextOccurrenceId == "" && to.Task.NextOccurrenceId != "" {
+		event := newTaskEvent(TaskRecurred)
+		event.Occurrence = to.NextOccurrenceKey
+
+		ret = append(ret, event)
+	}
+
+	return ret
+}
+
+// taskContentEqual is tasksEqual without the history and the comments of the tasks,
+// which are only ever added to, without the next occurrence of the task, which
+// is recorded as it recurring, and without the timestamps kept along with changes.
+func taskContentEqual(a *Task, b *Task) bool {
+	aContent := *a
+	aContent.Events = nil
+	aContent.Comments = nil
+	aContent.NextOccurrenceId = ""
+	aContent.clearTimestamps()
+	bContent := *b
+	bContent.Events = nil
+	bContent.Comments = nil
+	bContent.NextOccurrenceId = ""
+	bContent.clearTimestamps()
+
+	return tasksEqual(&aContent, &bContent)
+}
+
+// mergeTaskEvents merges the histories of a task changed by two processes. Events
+// are only appended, so everything after the base on either side is kept.
+func mergeTaskEvents(base []*TaskEvent, ours []*TaskEvent, theirs []*TaskEvent) []*TaskEvent {
+	ret := slices.Clone(ours)
+
+	if len(theirs) > len(base) {
+		ret = append(ret, theirs[len(base):]...)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].At.Before(ret[j].At)
+	})
+
+	return ret
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/okira-e/gotasks/internal/opt"
+)
+
+// maxTaskKeyPrefixLength is how long the prefix of task keys can be.
+const maxTaskKeyPrefixLength = 10
+
+// DefaultTaskKeyPrefix makes up the prefix of task keys for a board with the given
+// name, like "API" for "api-server" or "GT" for "gotasks". It's the initials of the
+// words of the name if there are a few, or the start of the name otherwise.
+func DefaultTaskKeyPrefix(boardName string) string {
+	// Boards that share a name are told apart by their parent directories, like
+	// "client/api". The last part is the name of the board itself.
+	if i := strings.LastIndex(boardName, "/"); i != -1 {
+		boardName = boardName[i+1:]
+	}
+
+	words := strings.FieldsFunc(boardName, func(r rune) bool {
+		return !isTaskKeyPrefixRune(r)
+	})
+
+	ret := ""
+	if len(words) > 1 {
+		for _, word := range words {
+			ret += string([]rune(word)[0])
+		}
+	} else if len(words) == 1 {
+		ret = string([]rune(words[0])[:min(3, len([]rune(words[0])))])
+	}
+
+	ret = strings.ToUpper(ret)
+	if ret == "" || !unicode.IsLetter([]rune(ret)[0]) {
+		ret = "T" + ret
+	}
+
+	return string([]rune(ret)[:min(maxTaskKeyPrefixLength, len([]rune(ret)))])
+}
+
+// ParseTaskKeyPrefix reads a prefix for task keys. It has to start with a letter and
+// only have letters and digits in it.
+func ParseTaskKeyPrefix(text string) (string, error) {
+	text = strings.ToUpper(strings.TrimSpace(text))
+
+	if text == "" {
+		return "", fmt.Errorf("The prefix of task keys can't be empty")
+	}
+
+	runes := []rune(text)
+	if len(runes) > maxTaskKeyPrefixLength {
+		return "", fmt.Errorf("The prefix of task keys can be up to %d characters long", maxTaskKeyPrefixLength)
+	}
+
+	if !unicode.IsLetter(runes[0]) || slices.ContainsFunc(runes, func(r rune) bool { return !isTaskKeyPrefixRune(r) }) {
+		return "", fmt.Errorf("The prefix \"%s\" should start with a letter and only have letters and digits in it", text)
+	}
+
+	return text, nil
+}
+
+func isTaskKeyPrefixRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// GetTaskKey returns the key of the task on the board, like "API-42". Tasks that
+// don't have a number yet go by the start of their ID instead.
+func (board *Board) GetTaskKey(task *Task) string {
+	if task.Number == 0 {
+		return task.ShortId()
+	}
+
+	return board.TaskKeyPrefix + "-" + strconv.Itoa(task.Number)
+}
+
+// matchesTaskKey reports if the text is the key of the task, in any case.
+func (board *Board) matchesTaskKey(task *Task, text string) bool {
+	return task.Number != 0 && strings.EqualFold(board.GetTaskKey(task), strings.TrimSpace(text))
+}
+
+// GetTaskByKey searches every column of the board for the task with the given key,
+// like "API-42". The case of the key doesn't matter.
+func (board *Board) GetTaskByKey(key string) opt.Option[*Task] {
+	for _, column := range board.Columns {
+		for _, it := range board.Tasks[column.Id] {
+			if board.matchesTaskKey(it, key) {
+				return opt.Some(it)
+			}
+		}
+	}
+
+	return opt.None[*Task]()
+}
+
+// assignTaskNumber gives the task the next number of the board if it has none. The
+// counter only goes up, so a key is never given twice even if its task was deleted.
+func (board *Board) assignTaskNumber(task *Task) {
+	if task.Number != 0 {
+		return
+	}
+
+	if board.TaskKeyPrefix == "" {
+		board.TaskKeyPrefix = DefaultTaskKeyPrefix(board.Name)
+	}
+
+	board.LastTaskNumber++
+	task.Number = board.LastTaskNumber
+}
+
+// SetTaskKeyPrefix changes the prefix of the keys of every task on the board, like
+// from "API-42" to "SRV-42". The numbers of the tasks stay as they are.
+func (self *UserConfig) SetTaskKeyPrefix(boardId string, prefix string) error {
+	prefix, err := ParseTaskKeyPrefix(prefix)
+	if err != nil {
+		return err
+	}
+
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return fmt.Errorf("Couldn't find the board while trying to set the prefix of its task keys")
+	}
+
+	board := boardOpt.Unwrap()
+	board.TaskKeyPrefix = prefix
+
+	return self.UpdateBoard(board)
+}
+
+// migrateTaskNumbers gives every task of the board a number, oldest first, and the
+// board a prefix for their keys. Tasks in the archive and the trash are numbered too,
+// so restoring them doesn't bring back a key that was given again.
+func migrateTaskNumbers(board document) error {
+	name, _ := board["name"].(string)
+	if prefix, _ := board["task_key_prefix"].(string); prefix == "" {
+		board["task_key_prefix"] = DefaultTaskKeyPrefix(name)
+	}
+
+	// Tasks created at the same time are numbered in the order they're shown in: by
+	// their column, then by where they are in it, then by their ID. Tasks in columns
+	// the board doesn't list come after, then the archive, then the trash.
+	type numberedTask struct {
+		task     document
+		column   int
+		position int
+	}
+
+	columnNames := []string{}
+	columns, _ := board["columns"].([]any)
+	for _, it := range columns {
+		if name, ok := it.(string); ok {
+			columnNames = append(columnNames, name)
+		}
+	}
+
+	boardTasks, _ := board["tasks"].(document)
+	for _, name := range slices.Sorted(maps.Keys(boardTasks)) {
+		if !slices.Contains(columnNames, name) {
+			columnNames = append(columnNames, name)
+		}
+	}
+
+	tasks := []numberedTask{}
+	for i, name := range columnNames {
+		columnTasks, _ := boardTasks[name].([]any)
+		for position, it := range columnTasks {
+			if task, ok := it.(document); ok {
+				tasks = append(tasks, numberedTask{task, i, position})
+			}
+		}
+	}
+	for i, shelf := range []string{"archive", "trash"} {
+		removedTasks, _ := board[shelf].([]any)
+		for position, it := range removedTasks {
+			removed, _ := it.(document)
+			if task, ok := removed["task"].(document); ok {
+				tasks = append(tasks, numberedTask{task, len(columnNames) + i, position})
+			}
+		}
+	}
+
+	// created_at is in RFC3339 since the migration before this one, so it sorts as text.
+	slices.SortFunc(tasks, func(a numberedTask, b numberedTask) int {
+		aCreatedAt, _ := a.task["created_at"].(string)
+		bCreatedAt, _ := b.task["created_at"].(string)
+		aId, _ := a.task["id"].(string)
+		bId, _ := b.task["id"].(string)
+
+		return cmp.Or(
+			strings.Compare(aCreatedAt, bCreatedAt),
+			cmp.Compare(a.column, b.column),
+			cmp.Compare(a.position, b.position),
+			strings.Compare(aId, bId),
+		)
+	})
+
+	lastNumber := 0
+	for _, it := range tasks {
+		lastNumber++
+		it.task["number"] = lastNumber
+	}
+
+	board["last_task_number"] = lastNumber
+
+	return nil
+}
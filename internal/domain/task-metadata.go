@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DueDateLayout is the layout due dates are kept and typed in.
+const DueDateLayout = "2006-01-02"
+
+// TaskPriority is how urgent a task is. The empty priority means none was set.
+type TaskPriority string
+
+const (
+	PriorityNone   TaskPriority = ""
+	PriorityLow    TaskPriority = "low"
+	PriorityMedium TaskPriority = "medium"
+	PriorityHigh   TaskPriority = "high"
+	PriorityUrgent TaskPriority = "urgent"
+)
+
+// EstimateUnit is what an estimate is counted in.
+type EstimateUnit string
+
+const (
+	EstimatePoints EstimateUnit = "points"
+	EstimateHours  EstimateUnit = "hours"
+)
+
+// TaskEstimate is how much work a task is expected to take.
+type TaskEstimate struct {
+	Value float64      `json:"value"`
+	Unit  EstimateUnit `json:"unit"`
+}
+
+// String writes the estimate the way ParseEstimate reads it, like "3p" or "1.5h".
+func (estimate *TaskEstimate) String() string {
+	if estimate == nil {
+		return ""
+	}
+
+	value := strconv.FormatFloat(estimate.Value, 'f', -1, 64)
+	if estimate.Unit == EstimateHours {
+		return value + "h"
+	}
+
+	return value + "p"
+}
+
+// ParsePriority reads a priority by its name, or by the first letter of it.
+func ParsePriority(text string) (TaskPriority, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return PriorityNone, nil
+	}
+
+	for _, it := range []TaskPriority{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent} {
+		if text == string(it) || text == string(it)[:1] {
+			return it, nil
+		}
+	}
+
+	return PriorityNone, fmt.Errorf("Unknown priority \"%s\". It could be \"low\", \"medium\", \"high\" or \"urgent\"", text)
+}
+
+// ParseDueDate reads a due date in the DueDateLayout, or "today" or "tomorrow".
+func ParseDueDate(text string) (string, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	switch text {
+	case "":
+		return "", nil
+	case "today":
+		return time.Now().Format(DueDateLayout), nil
+	case "tomorrow":
+		return time.Now().AddDate(0, 0, 1).Format(DueDateLayout), nil
+	}
+
+	parsed, err := time.Parse(DueDateLayout, text)
+	if err != nil {
+		return "", fmt.Errorf("The due date \"%s\" should look like YYYY-MM-DD", text)
+	}
+
+	return parsed.Format(DueDateLayout), nil
+}
+
+// ParseLabels reads comma separated labels, dropping empty and repeated ones.
+func ParseLabels(text string) []string {
+	ret := []string{}
+	seen := map[string]bool{}
+
+	for _, it := range strings.Split(text, ",") {
+		label := strings.TrimPrefix(strings.TrimSpace(it), "#")
+		if label == "" || seen[label] {
+			continue
+		}
+
+		seen[label] = true
+		ret = append(ret, label)
+	}
+
+	return ret
+}
+
+// ParseEstimate reads an estimate like "3", "3p" or "3 points" for points, and
+// "4h" or "4 hours" for hours. Numbers without a unit are points.
+func ParseEstimate(text string) (*TaskEstimate, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return nil, nil
+	}
+
+	units := []struct {
+		suffixes []string
+		unit     EstimateUnit
+	}{
+		{[]string{"hours", "hour", "hrs", "hr", "h"}, EstimateHours},
+		{[]string{"points", "point", "pts", "pt", "p"}, EstimatePoints},
+	}
+
+	ret := new(TaskEstimate)
+	ret.Unit = EstimatePoints
+
+	number := text
+	for _, it := range units {
+		found := false
+
+		for _, suffix := range it.suffixes {
+			if strings.HasSuffix(text, suffix) {
+				number = strings.TrimSpace(strings.TrimSuffix(text, suffix))
+				ret.Unit = it.unit
+				found = true
+				break
+			}
+		}
+
+		if found {
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return nil, fmt.Errorf("The estimate \"%s\" should be a number of points or hours, like \"3p\" or \"4h\"", text)
+	}
+
+	ret.Value = value
+
+	return ret, nil
+}
+
+// IsHighPriority reports if the task is of high priority or more.
+func (task *Task) IsHighPriority() bool {
+	return task.Priority == PriorityHigh || task.Priority == PriorityUrgent
+}
+
+// IsOverdue reports if the due date of the task has passed while it's still not done
+// or cancelled.
+func (board *Board) IsOverdue(task *Task) bool {
+	if task.DueDate == "" || board.IsClosed(task) {
+		return false
+	}
+
+	return task.DueDate < time.Now().Format(DueDateLayout)
+}
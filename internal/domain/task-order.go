@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// Tasks are kept in their column from the bottom to the top, so the last task of a
+// column is the one shown on top of it. Positions are counted from the top, as the
+// tasks are shown.
+
+// TaskPlacement is where a task lands in the column it's moved to.
+type TaskPlacement int
+
+const (
+	PlaceOnTop TaskPlacement = iota
+	PlaceAtBottom
+)
+
+// insertTask puts the task in the column with the given ID, on top of it or at its bottom.
+func (board *Board) insertTask(columnId string, task *Task, placement TaskPlacement) {
+	if placement == PlaceAtBottom {
+		board.Tasks[columnId] = append([]*Task{task}, board.Tasks[columnId]...)
+		return
+	}
+
+	board.Tasks[columnId] = append(board.Tasks[columnId], task)
+}
+
+// GetTaskPosition returns where the task is in its column, counting from the top
+// starting at 0, or -1 if the task isn't on the board.
+func (board *Board) GetTaskPosition(task *Task) int {
+	column, _ := board.GetColumnForTask(task)
+	if column == nil {
+		return -1
+	}
+
+	tasks := board.Tasks[column.Id]
+	for i, it := range tasks {
+		if it == task {
+			return len(tasks) - 1 - i
+		}
+	}
+
+	return -1
+}
+
+// MoveTaskWithinColumn moves the task to the given position in its column, counting
+// from the top starting at 0. Positions out of range put it on top or at the bottom.
+func (self *UserConfig) MoveTaskWithinColumn(board *Board, task *Task, position int) error {
+	column, _ := board.GetColumnForTask(task)
+	if column == nil {
+		return errors.New("Couldn't find the column of the task to move")
+	}
+
+	tasks := board.Tasks[column.Id]
+	position = min(max(position, 0), len(tasks) - 1)
+
+	oldPosition := board.GetTaskPosition(task)
+	if position == oldPosition {
+		return nil
+	}
+
+	description := fmt.Sprintf("Move \"%s\" %s in %s", task.Title, utils.Cond(position < oldPosition, "up", "down"), column.Name)
+	if position == 0 {
+		description = fmt.Sprintf("Move \"%s\" to the top of %s", task.Title, column.Name)
+	} else if position == len(tasks) - 1 {
+		description = fmt.Sprintf("Move \"%s\" to the bottom of %s", task.Title, column.Name)
+	}
+
+	return self.runCommand(board, description, []string{task.Id}, func() error {
+		board.removeTaskFromColumn(task, column.Id)
+
+		// The position is counted from the top, while the tasks are kept from the bottom.
+		i := len(board.Tasks[column.Id]) - position
+		board.Tasks[column.Id] = slices.Insert(board.Tasks[column.Id], i, task)
+
+		return nil
+	})
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,11 +9,48 @@ import (
 
 type Task struct {
 	Id    string `json:"id"`
+	// Number is what the key of the task is made of along with the prefix of its
+	// board, like 42 in "API-42". It's given when the task is added to the board.
+	Number	int `json:"number,omitempty"`
 	Title string `json:"title"`
 	// Optional
 	Description string `json:"description"`
-	// Column      string `json:"column"`
-	CreatedAt   string `json:"created_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	// UpdatedAt is the last time the task was changed or moved.
+	UpdatedAt   time.Time `json:"updated_at"`
+	// StartedAt is when the task was first moved to an active column. It's nil if
+	// it never was.
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	// CompletedAt is when the task was moved to the done column it's in. It's nil
+	// if it isn't in one.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Optional
+	Priority	TaskPriority `json:"priority,omitempty"`
+	// Optional. DueDate is in DueDateLayout.
+	DueDate		string `json:"due_date,omitempty"`
+	// Optional
+	Labels		[]string `json:"labels,omitempty"`
+	// Optional
+	Assignee	string `json:"assignee,omitempty"`
+	// Optional
+	Estimate	*TaskEstimate `json:"estimate,omitempty"`
+	// Optional. Epic is the name of the bigger piece of work the task is a part of.
+	Epic		string `json:"epic,omitempty"`
+	// Optional. Recurrence is how often the task comes back. See recurrence.go.
+	Recurrence	*TaskRecurrence `json:"recurrence,omitempty"`
+	// PreviousOccurrenceId and NextOccurrenceId are the IDs of the occurrences of a
+	// recurring task that came before and after this one.
+	PreviousOccurrenceId	string `json:"previous_occurrence_id,omitempty"`
+	NextOccurrenceId		string `json:"next_occurrence_id,omitempty"`
+	// Optional. BlockedBy holds the IDs of the tasks on the same board that have to
+	// be done before this one.
+	BlockedBy	[]string `json:"blocked_by,omitempty"`
+	// Optional. The steps of the task, in order.
+	Checklist	[]*ChecklistItem `json:"checklist,omitempty"`
+	// Comments are the notes left on the task, oldest first.
+	Comments	[]*TaskComment `json:"comments,omitempty"`
+	// Events is the history of the task, oldest first. See GetEvents.
+	Events		[]*TaskEvent `json:"events,omitempty"`
 }
 
 func NewTask(title string, description string) *Task {
@@ -23,7 +61,51 @@ func NewTask(title string, description string) *Task {
 	ret.Id = id.String()
 	ret.Title = title
 	ret.Description = description
-	ret.CreatedAt = time.Now().UTC().String()
+	ret.CreatedAt = timestampNow()
+	ret.UpdatedAt = ret.CreatedAt
 	
 	return ret
 }
+
+// copy returns a copy of the task that doesn't share anything with it.
+func (task *Task) copy() *Task {
+	ret := *task
+	ret.Labels = slices.Clone(task.Labels)
+	ret.BlockedBy = slices.Clone(task.BlockedBy)
+	ret.Events = slices.Clone(task.Events)
+	// Comments are never changed once added, so they can be shared.
+	ret.Comments = slices.Clone(task.Comments)
+	ret.Checklist = nil
+	for _, it := range task.Checklist {
+		item := *it
+		ret.Checklist = append(ret.Checklist, &item)
+	}
+	if task.Estimate != nil {
+		estimate := *task.Estimate
+		ret.Estimate = &estimate
+	}
+	if task.Recurrence != nil {
+		recurrence := *task.Recurrence
+		ret.Recurrence = &recurrence
+	}
+	if task.StartedAt != nil {
+		startedAt := *task.StartedAt
+		ret.StartedAt = &startedAt
+	}
+	if task.CompletedAt != nil {
+		completedAt := *task.CompletedAt
+		ret.CompletedAt = &completedAt
+	}
+	
+	return &ret
+}
+
+// ShortId is the start of the ID of the task, which is usually enough to tell
+// it apart from the others.
+func (task *Task) ShortId() string {
+	if len(task.Id) <= 8 {
+		return task.Id
+	}
+	
+	return task.Id[:8]
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// TaskTemplate is what a task can start from, like a bug report with the skeleton of
+// its description and its labels. Templates are kept on a board, or globally in the
+// config for every board. A board template takes the place of a global one with the
+// same name.
+type TaskTemplate struct {
+	Name string `json:"name"`
+	// Title is the pattern of the title of the tasks made from the template, like
+	// "Bug: {title}". "{title}" is replaced by the title typed for the task and "{date}"
+	// by the date of the day. A title typed for a pattern without "{title}" is kept as
+	// it is. Optional.
+	Title string `json:"title,omitempty"`
+	// Optional
+	Description string `json:"description,omitempty"`
+	// Optional
+	Labels []string `json:"labels,omitempty"`
+	// Optional. The text of the items of the checklist, in order.
+	Checklist []string `json:"checklist,omitempty"`
+}
+
+func NewTaskTemplate(name string) *TaskTemplate {
+	ret := new(TaskTemplate)
+
+	ret.Name = strings.TrimSpace(name)
+
+	return ret
+}
+
+// GetTitle returns the title of a task made from the template, going by its title
+// pattern, with the given title typed for it.
+func (template *TaskTemplate) GetTitle(title string) string {
+	title = strings.TrimSpace(title)
+	if template.Title == "" {
+		return title
+	}
+
+	if !strings.Contains(template.Title, "{title}") && title != "" {
+		return title
+	}
+
+	ret := strings.ReplaceAll(template.Title, "{date}", time.Now().Format(DueDateLayout))
+	ret = strings.ReplaceAll(ret, "{title}", title)
+
+	return strings.TrimSpace(ret)
+}
+
+// ApplyTo sets the title, the description, the labels and the checklist of the task
+// going by the template. The title typed for the task is used in the title pattern,
+// a description that was already written is kept under the one of the template, and
+// the labels of the task are kept along with the ones of the template.
+func (template *TaskTemplate) ApplyTo(task *Task) {
+	task.Title = template.GetTitle(task.Title)
+
+	if template.Description != "" {
+		task.Description = strings.TrimSpace(template.Description + "\n\n" + task.Description)
+	}
+
+	for _, label := range template.Labels {
+		if !slices.Contains(task.Labels, label) {
+			task.Labels = append(task.Labels, label)
+		}
+	}
+
+	for _, text := range template.Checklist {
+		task.Checklist = append(task.Checklist, newChecklistItem(text))
+	}
+}
+
+// NewTask returns a task made from the template, with the given title typed for it.
+func (template *TaskTemplate) NewTask(title string) *Task {
+	ret := NewTask(title, "")
+
+	template.ApplyTo(ret)
+
+	return ret
+}
+
+// GetTemplates returns the templates the tasks of the board can start from: the ones
+// of the board first, then the global ones that none of them takes the place of.
+func (self *UserConfig) GetTemplates(board *Board) []*TaskTemplate {
+	ret := slices.Clone(board.Templates)
+
+	for _, template := range self.Templates {
+		if findTemplate(board.Templates, template.Name) == nil {
+			ret = append(ret, template)
+		}
+	}
+
+	return ret
+}
+
+// IsBoardTemplate reports if the template is one of the board's rather than a global one.
+func (board *Board) IsBoardTemplate(template *TaskTemplate) bool {
+	return slices.Contains(board.Templates, template)
+}
+
+// FindTemplate returns the template with the given name that the tasks of the board
+// can start from. Names are matched regardless of their case.
+func (self *UserConfig) FindTemplate(board *Board, name string) (*TaskTemplate, error) {
+	template := findTemplate(self.GetTemplates(board), name)
+	if template == nil {
+		return nil, fmt.Errorf("Couldn't find a template named \"%s\". Run \"gotasks template\" to list them", name)
+	}
+
+	return template, nil
+}
+
+func findTemplate(templates []*TaskTemplate, name string) *TaskTemplate {
+	name = strings.TrimSpace(name)
+
+	for _, it := range templates {
+		if strings.EqualFold(it.Name, name) {
+			return it
+		}
+	}
+
+	return nil
+}
+
+// SaveTemplate adds the template to the board, or globally, taking the place of the
+// one there with the same name if any. The board can be nil for global templates.
+func (self *UserConfig) SaveTemplate(board *Board, template *TaskTemplate, global bool) error {
+	if template.Name == "" {
+		return errors.New("A template has to have a name")
+	}
+
+	if global {
+		self.Templates = saveTemplate(self.Templates, template)
+		return self.saveConfig()
+	}
+
+	board.Templates = saveTemplate(board.Templates, template)
+
+	return self.UpdateBoard(board)
+}
+
+func saveTemplate(templates []*TaskTemplate, template *TaskTemplate) []*TaskTemplate {
+	for i, it := range templates {
+		if strings.EqualFold(it.Name, template.Name) {
+			templates[i] = template
+			return templates
+		}
+	}
+
+	return append(templates, template)
+}
+
+// DeleteTemplate deletes the template with the given name from the board, or from the
+// global ones. The board can be nil for global templates.
+func (self *UserConfig) DeleteTemplate(board *Board, name string, global bool) error {
+	var templates *[]*TaskTemplate
+	if global {
+		templates = &self.Templates
+	} else {
+		templates = &board.Templates
+	}
+
+	template := findTemplate(*templates, name)
+	if template == nil {
+		return fmt.Errorf("Couldn't find a %s template named \"%s\"", utils.Cond(global, "global", "board"), name)
+	}
+
+	*templates = slices.DeleteFunc(*templates, func(it *TaskTemplate) bool { return it == template })
+
+	if global {
+		return self.saveConfig()
+	}
+
+	return self.UpdateBoard(board)
+}
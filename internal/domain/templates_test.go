@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTaskTemplateGetTitle(t *testing.T) {
+	date := time.Now().Format(DueDateLayout)
+
+	tests := []struct {
+		name    string
+		pattern string
+		typed   string
+		want    string
+	}{
+		{
+			name:  "a template without a pattern keeps what's typed",
+			typed: "Fix the login",
+			want:  "Fix the login",
+		},
+		{
+			name:    "{title} is replaced by what's typed",
+			pattern: "Bug: {title}",
+			typed:   "  the login crashes ",
+			want:    "Bug: the login crashes",
+		},
+		{
+			name:    "{title} with nothing typed",
+			pattern: "Bug: {title}",
+			want:    "Bug:",
+		},
+		{
+			name:    "{date} is replaced by the date of the day",
+			pattern: "Standup {date}",
+			want:    "Standup " + date,
+		},
+		{
+			name:    "{date} along with {title}",
+			pattern: "{date} {title}",
+			typed:   "notes",
+			want:    date + " notes",
+		},
+		{
+			name:    "a title typed for a pattern without {title} is kept as it is",
+			pattern: "Weekly review",
+			typed:   "Review of the release",
+			want:    "Review of the release",
+		},
+		{
+			name:    "a pattern without {title} with nothing typed",
+			pattern: "Weekly review {date}",
+			want:    "Weekly review " + date,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			template := NewTaskTemplate("template")
+			template.Title = test.pattern
+
+			if got := template.GetTitle(test.typed); got != test.want {
+				t.Errorf("Expected the title to be \"%s\", got \"%s\"", test.want, got)
+			}
+		})
+	}
+}
+
+func TestTaskTemplateApplyTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *TaskTemplate
+		task     *Task
+		// want is the task once the template is applied to it, other than its checklist.
+		want          *Task
+		wantChecklist []string
+	}{
+		{
+			name: "an empty task",
+			template: &TaskTemplate{
+				Name:        "bug",
+				Title:       "Bug: {title}",
+				Description: "Steps to reproduce:",
+				Labels:      []string{"bug"},
+				Checklist:   []string{"Reproduce", "Fix"},
+			},
+			task:          &Task{Title: "crash"},
+			want:          &Task{Title: "Bug: crash", Description: "Steps to reproduce:", Labels: []string{"bug"}},
+			wantChecklist: []string{"Reproduce", "Fix"},
+		},
+		{
+			name: "a description that was written is kept under the one of the template",
+			template: &TaskTemplate{
+				Name:        "bug",
+				Description: "Steps to reproduce:",
+			},
+			task: &Task{Title: "crash", Description: "Open the app."},
+			want: &Task{Title: "crash", Description: "Steps to reproduce:\n\nOpen the app."},
+		},
+		{
+			name: "labels the task already has aren't added again",
+			template: &TaskTemplate{
+				Name:   "bug",
+				Labels: []string{"bug", "backend"},
+			},
+			task: &Task{Title: "crash", Labels: []string{"urgent", "bug"}},
+			want: &Task{Title: "crash", Labels: []string{"urgent", "bug", "backend"}},
+		},
+		{
+			name: "the checklist of the template is added after the one of the task",
+			template: &TaskTemplate{
+				Name:      "release",
+				Checklist: []string{"Tag", "Publish"},
+			},
+			task:          &Task{Title: "v2", Checklist: []*ChecklistItem{newChecklistItem("Changelog")}},
+			want:          &Task{Title: "v2"},
+			wantChecklist: []string{"Changelog", "Tag", "Publish"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.template.ApplyTo(test.task)
+
+			if test.task.Title != test.want.Title {
+				t.Errorf("Expected the title to be \"%s\", got \"%s\"", test.want.Title, test.task.Title)
+			}
+			if test.task.Description != test.want.Description {
+				t.Errorf("Expected the description to be %q, got %q", test.want.Description, test.task.Description)
+			}
+			if !slices.Equal(test.task.Labels, test.want.Labels) {
+				t.Errorf("Expected the labels to be %v, got %v", test.want.Labels, test.task.Labels)
+			}
+
+			checklist := []string{}
+			for _, it := range test.task.Checklist {
+				checklist = append(checklist, it.Text)
+				if it.Done {
+					t.Errorf("Expected \"%s\" not to be checked", it.Text)
+				}
+			}
+			if !slices.Equal(checklist, test.wantChecklist) {
+				t.Errorf("Expected the checklist to be %v, got %v", test.wantChecklist, checklist)
+			}
+		})
+	}
+}
+
+func TestFindTemplate(t *testing.T) {
+	config, board := newTestConfig(t)
+	other, err := config.CreateBoard("web", "/projects/web")
+	if err != nil {
+		t.Fatalf("Failed to create the board. %s", err)
+	}
+
+	mustSucceed(t, config.SaveTemplate(nil, &TaskTemplate{Name: "bug", Title: "Bug: {title}"}, true))
+	mustSucceed(t, config.SaveTemplate(nil, &TaskTemplate{Name: "chore", Title: "Chore: {title}"}, true))
+	mustSucceed(t, config.SaveTemplate(board, &TaskTemplate{Name: "Bug", Title: "API bug: {title}"}, false))
+
+	tests := []struct {
+		name     string
+		board    *Board
+		template string
+		// want is the title pattern of the template found, which is empty if none is.
+		want string
+	}{
+		{
+			name:     "a board template takes the place of a global one with the same name",
+			board:    board,
+			template: "bug",
+			want:     "API bug: {title}",
+		},
+		{
+			name:     "names are matched regardless of their case",
+			board:    board,
+			template: " BUG ",
+			want:     "API bug: {title}",
+		},
+		{
+			name:     "a global template the board doesn't have one for",
+			board:    board,
+			template: "chore",
+			want:     "Chore: {title}",
+		},
+		{
+			name:     "another board gets the global template",
+			board:    other,
+			template: "bug",
+			want:     "Bug: {title}",
+		},
+		{
+			name:     "a template that isn't there",
+			board:    board,
+			template: "feature",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The templates are saved, so the board template still takes the place of
+			// the global one once the config is read again.
+			for _, it := range []*UserConfig{config, reopenConfig(t, config)} {
+				template, err := it.FindTemplate(openTestBoard(t, it, test.board.Id), test.template)
+				if test.want == "" {
+					if err == nil {
+						t.Errorf("Expected no template to be found, got \"%s\"", template.Name)
+					}
+					continue
+				}
+
+				if err != nil {
+					t.Fatalf("Failed to find the template. %s", err)
+				}
+				if template.Title != test.want {
+					t.Errorf("Expected the template \"%s\", got \"%s\"", test.want, template.Title)
+				}
+			}
+		})
+	}
+
+	// The templates of the board come first, followed by the global ones it doesn't
+	// have its own of.
+	names := []string{}
+	for _, template := range config.GetTemplates(board) {
+		names = append(names, template.Name)
+	}
+	if !slices.Equal(names, []string{"Bug", "chore"}) {
+		t.Errorf("Expected the templates of the board to be [Bug chore], got %v", names)
+	}
+}
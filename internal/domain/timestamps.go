@@ -0,0 +1,272 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// timestampNow returns the time to record on a task, in UTC and to the second like
+// the rest of the timestamps that are persisted.
+func timestampNow() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// updateTaskTimestamps keeps the timestamps of a task up to date after a command took
+// it from one snapshot to another. Either snapshot is nil if the task isn't on the
+// board in it.
+func (board *Board) updateTaskTimestamps(from *TaskSnapshot, to *TaskSnapshot, now time.Time) {
+	if to == nil {
+		return
+	}
+
+	task := board.findTask(to.Task.Id)
+	if task == nil {
+		return
+	}
+
+	movedColumn := from == nil || from.Column != to.Column
+
+	if movedColumn || from.Shelf != to.Shelf || from.Position != to.Position || !tasksEqual(from.Task, to.Task) {
+		task.UpdatedAt = now
+	}
+
+	// Tasks taken to the archive or the trash, or brought back from them, stay in the
+	// column they were in.
+	if movedColumn && to.Shelf == OnBoard {
+		if column := board.GetColumnById(to.Column); column.IsSome() {
+			task.enterColumn(column.Unwrap(), now)
+		}
+	}
+}
+
+// enterColumn records the task being started or completed going by the role of the
+// column it was moved to. Leaving the done columns makes the task not completed anymore.
+func (task *Task) enterColumn(column *Column, now time.Time) {
+	switch column.Role {
+	case ActiveRole:
+		if task.StartedAt == nil {
+			task.StartedAt = &now
+		}
+		task.CompletedAt = nil
+	case DoneRole:
+		task.CompletedAt = &now
+	default:
+		task.CompletedAt = nil
+	}
+}
+
+// clearTimestamps clears the timestamps that are kept up to date along with the
+// changes to the task, leaving when it was created.
+func (task *Task) clearTimestamps() {
+	task.UpdatedAt = time.Time{}
+	task.StartedAt = nil
+	task.CompletedAt = nil
+}
+
+// GetAge returns how long ago the task was created, or completed if it's done.
+func (board *Board) GetAge(task *Task) time.Duration {
+	if task.CompletedAt != nil && board.IsDone(task) {
+		return time.Since(*task.CompletedAt)
+	}
+
+	return time.Since(task.CreatedAt)
+}
+
+// SetShowTaskAges sets if the cards of the board show how old their tasks are.
+func (self *UserConfig) SetShowTaskAges(boardId string, show bool) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to show the ages of its tasks")
+	}
+
+	board := boardOpt.Unwrap()
+	board.ShowTaskAges = show
+
+	return self.UpdateBoard(board)
+}
+
+// mergeTaskTimestamps sets the timestamps of a task changed by two processes once
+// its content is merged. The task was last updated whenever either side did, and it
+// was started and completed going by the side whose column it ends up in.
+func mergeTaskTimestamps(merged *Task, ours *Task, theirs *Task, theirColumnWins bool) {
+	merged.UpdatedAt = ours.UpdatedAt
+	if theirs.UpdatedAt.After(ours.UpdatedAt) {
+		merged.UpdatedAt = theirs.UpdatedAt
+	}
+
+	from := ours
+	if theirColumnWins {
+		from = theirs
+	}
+
+	merged.StartedAt = from.StartedAt
+	merged.CompletedAt = from.CompletedAt
+}
+
+// migrateTaskTimestamps backfills when every task was last updated, started and
+// completed, going by its history and the column it's in.
+func migrateTaskTimestamps(board document) error {
+	rolesById := map[string]ColumnRole{}
+	// The history of a task has the names of the columns it was moved between.
+	rolesByName := map[string]ColumnRole{}
+
+	columns, _ := board["columns"].([]any)
+	for _, it := range columns {
+		column, ok := it.(document)
+		if !ok {
+			continue
+		}
+
+		columnId, _ := column["id"].(string)
+		name, _ := column["name"].(string)
+		role, _ := column["role"].(string)
+
+		rolesById[columnId] = ColumnRole(role)
+		rolesByName[name] = ColumnRole(role)
+	}
+
+	backfill := func(task document, columnId string) {
+		createdAt, _ := task["created_at"].(string)
+		updatedAt := createdAt
+		startedAt := ""
+		completedAt := ""
+
+		events, _ := task["events"].([]any)
+		for _, it := range events {
+			event, ok := it.(document)
+			if !ok {
+				continue
+			}
+
+			at, _ := event["at"].(string)
+			if at == "" {
+				continue
+			}
+			updatedAt = at
+
+			if kind, _ := event["kind"].(string); kind != string(TaskMoved) {
+				continue
+			}
+
+			toColumn, _ := event["to_column"].(string)
+			switch rolesByName[toColumn] {
+			case ActiveRole:
+				if startedAt == "" {
+					startedAt = at
+				}
+				completedAt = ""
+			case DoneRole:
+				completedAt = at
+			case BacklogRole, CancelledRole:
+				completedAt = ""
+			}
+		}
+
+		// Tasks from before their moves were recorded were started and completed at
+		// some point, the last time they were changed is the closest to it.
+		role := rolesById[columnId]
+		if role == ActiveRole && startedAt == "" {
+			startedAt = updatedAt
+		}
+		if role == DoneRole && completedAt == "" {
+			completedAt = updatedAt
+		}
+		if role != DoneRole {
+			completedAt = ""
+		}
+
+		task["updated_at"] = updatedAt
+		if startedAt != "" {
+			task["started_at"] = startedAt
+		}
+		if completedAt != "" {
+			task["completed_at"] = completedAt
+		}
+	}
+
+	tasks, _ := board["tasks"].(document)
+	for columnId, it := range tasks {
+		columnTasks, _ := it.([]any)
+		for _, it := range columnTasks {
+			if task, ok := it.(document); ok {
+				backfill(task, columnId)
+			}
+		}
+	}
+
+	for _, shelf := range []string{"archive", "trash"} {
+		removedTasks, _ := board[shelf].([]any)
+		for _, it := range removedTasks {
+			removed, ok := it.(document)
+			if !ok {
+				continue
+			}
+
+			columnId, _ := removed["column"].(string)
+			if task, ok := removed["task"].(document); ok {
+				backfill(task, columnId)
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateRecordedTimes makes sure the times of the events and the comments of every
+// task, and of every task in the archive and the trash being taken there, can be
+// read as times. They're written in RFC3339, but a board edited by hand can have
+// them in any form, or missing. Times that can't be read are replaced with when the
+// task was created, or with now for when a task was taken off the board.
+func migrateRecordedTimes(board document) error {
+	now := timestampNow().Format(time.RFC3339)
+
+	normalize := func(doc document, key string, fallback string) {
+		text, _ := doc[key].(string)
+
+		parsed, err := parseLegacyTime(text)
+		if err != nil {
+			utils.SaveLog(utils.Warn, "Couldn't read a time of a task, replacing it", map[string]any{key: text})
+			doc[key] = fallback
+			return
+		}
+
+		doc[key] = parsed.UTC().Format(time.RFC3339)
+	}
+
+	normalizeTask := func(task document) {
+		createdAt, _ := task["created_at"].(string)
+
+		for _, key := range []string{"events", "comments"} {
+			records, _ := task[key].([]any)
+			for _, it := range records {
+				if record, ok := it.(document); ok {
+					normalize(record, "at", createdAt)
+				}
+			}
+		}
+	}
+
+	_ = forEachTaskDocument(board, func(task document) error {
+		normalizeTask(task)
+		return nil
+	})
+
+	for _, shelf := range []string{"archive", "trash"} {
+		removedTasks, _ := board[shelf].([]any)
+		for _, it := range removedTasks {
+			removed, ok := it.(document)
+			if !ok {
+				continue
+			}
+
+			normalize(removed, "removed_at", now)
+			if task, ok := removed["task"].(document); ok {
+				normalizeTask(task)
+			}
+		}
+	}
+
+	return nil
+}
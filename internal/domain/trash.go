@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/okira-e/gotasks/internal/opt"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// TaskShelf is where a task that isn't on the board's columns is kept.
+type TaskShelf string
+
+const (
+	// OnBoard is for tasks that are in one of the columns.
+	OnBoard      TaskShelf = ""
+	ArchiveShelf TaskShelf = "archive"
+	TrashShelf   TaskShelf = "trash"
+)
+
+// RemovedTask is a task taken off the board into the archive or the trash.
+type RemovedTask struct {
+	Task *Task `json:"task"`
+	// Column is the ID of the column the task was taken from. It goes back there when restored.
+	Column    string    `json:"column"`
+	RemovedAt time.Time `json:"removed_at"`
+}
+
+func newRemovedTask(task *Task, columnId string) *RemovedTask {
+	ret := new(RemovedTask)
+
+	ret.Task = task
+	ret.Column = columnId
+	ret.RemovedAt = timestampNow()
+
+	return ret
+}
+
+// GetShelf returns the tasks on the given shelf, the last one removed at the end.
+func (board *Board) GetShelf(shelf TaskShelf) []*RemovedTask {
+	return *board.shelf(shelf)
+}
+
+func (board *Board) shelf(shelf TaskShelf) *[]*RemovedTask {
+	if shelf == ArchiveShelf {
+		return &board.Archive
+	}
+
+	return &board.Trash
+}
+
+// GetRemovedTaskById searches the archive and the trash for the task with the given ID.
+func (board *Board) GetRemovedTaskById(taskId string) (opt.Option[*RemovedTask], TaskShelf) {
+	for _, shelf := range []TaskShelf{ArchiveShelf, TrashShelf} {
+		for _, it := range board.GetShelf(shelf) {
+			if it.Task.Id == taskId {
+				return opt.Some(it), shelf
+			}
+		}
+	}
+
+	return opt.None[*RemovedTask](), OnBoard
+}
+
+// FindRemovedTask searches the given shelf for the task with the given key or ID. A
+// prefix of the ID is enough as long as only one task on the shelf starts with it.
+func (board *Board) FindRemovedTask(shelf TaskShelf, taskId string) (*RemovedTask, error) {
+	var found *RemovedTask
+
+	for _, it := range board.GetShelf(shelf) {
+		if it.Task.Id == taskId || board.matchesTaskKey(it.Task, taskId) {
+			return it, nil
+		}
+
+		if taskId == "" || !strings.HasPrefix(it.Task.Id, taskId) {
+			continue
+		}
+
+		if found != nil {
+			return nil, fmt.Errorf("More than one task in the %s has an ID that starts with \"%s\"", shelf, taskId)
+		}
+
+		found = it
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("Couldn't find a task with the key or ID \"%s\" in the %s", taskId, shelf)
+	}
+
+	return found, nil
+}
+
+// ArchiveTask takes a done or a cancelled task off the board into its archive.
+func (self *UserConfig) ArchiveTask(boardId string, task *Task) error {
+	utils.SaveLog(utils.Debug, "Archiving a task", map[string]any{"task": task})
+
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to archive a task")
+	}
+
+	board := boardOpt.Unwrap()
+
+	if !board.IsClosed(task) {
+		return fmt.Errorf("Only done or cancelled tasks can be archived, \"%s\" is neither", task.Title)
+	}
+
+	column, _ := board.GetColumnForTask(task)
+
+	return self.runCommand(board, "Archive \"" + task.Title + "\"", []string{task.Id}, func() error {
+		board.removeTaskFromColumn(task, column.Id)
+		board.Archive = append(board.Archive, newRemovedTask(task, column.Id))
+
+		return nil
+	})
+}
+
+// ArchiveClosedTasks archives every task in the done and the cancelled columns of
+// the board. It returns how many tasks were archived.
+func (self *UserConfig) ArchiveClosedTasks(boardId string) (int, error) {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return 0, errors.New("Couldn't find the board while trying to archive its closed tasks")
+	}
+
+	board := boardOpt.Unwrap()
+
+	// The tasks are kept along with their column, since they're taken off the board
+	// while going through them.
+	tasks := []*Task{}
+	columnIds := []string{}
+	for _, column := range board.Columns {
+		if !column.IsClosed() {
+			continue
+		}
+
+		for _, task := range board.Tasks[column.Id] {
+			tasks = append(tasks, task)
+			columnIds = append(columnIds, column.Id)
+		}
+	}
+
+	if len(tasks) == 0 {
+		return 0, nil
+	}
+
+	taskIds := []string{}
+	for _, task := range tasks {
+		taskIds = append(taskIds, task.Id)
+	}
+
+	err := self.runCommand(board, fmt.Sprintf("Archive %d closed tasks", len(tasks)), taskIds, func() error {
+		for i, task := range tasks {
+			board.removeTaskFromColumn(task, columnIds[i])
+			board.Archive = append(board.Archive, newRemovedTask(task, columnIds[i]))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(tasks), nil
+}
+
+// RestoreTask puts a task from the archive or the trash back on top of the column
+// it was taken from, or of the backlog column if that one is gone.
+func (self *UserConfig) RestoreTask(boardId string, taskId string) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to restore a task")
+	}
+
+	board := boardOpt.Unwrap()
+
+	removedOpt, shelf := board.GetRemovedTaskById(taskId)
+	if removedOpt.IsNone() {
+		return fmt.Errorf("Couldn't find a task with the key or ID \"%s\" in the archive or the trash", taskId)
+	}
+
+	removed := removedOpt.Unwrap()
+
+	if len(board.Columns) == 0 {
+		return errors.New("No columns found to restore this task to.")
+	}
+
+	columnId := removed.Column
+	if !board.hasColumn(columnId) {
+		columnId = board.GetBacklogColumn().Id
+	}
+
+	return self.runCommand(board, "Restore \"" + removed.Task.Title + "\"", []string{taskId}, func() error {
+		board.removeTaskFromShelf(taskId, shelf)
+		board.Tasks[columnId] = append(board.Tasks[columnId], removed.Task)
+
+		return nil
+	})
+}
+
+// EmptyTrash takes every task out of the trash of the board. Like any other change
+// it can be undone, which puts them back in the trash, until it falls out of the
+// undo history. It returns how many tasks were taken out.
+func (self *UserConfig) EmptyTrash(boardId string) (int, error) {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return 0, errors.New("Couldn't find the board while trying to empty its trash")
+	}
+
+	board := boardOpt.Unwrap()
+
+	count := len(board.Trash)
+	if count == 0 {
+		return 0, nil
+	}
+
+	taskIds := []string{}
+	for _, it := range board.Trash {
+		taskIds = append(taskIds, it.Task.Id)
+	}
+
+	err := self.runCommand(board, "Empty the trash", taskIds, func() error {
+		board.Trash = nil
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (board *Board) removeTaskFromColumn(task *Task, columnId string) {
+	for i, it := range board.Tasks[columnId] {
+		if it == task {
+			board.Tasks[columnId] = append(board.Tasks[columnId][:i], board.Tasks[columnId][i+1:]...)
+			break
+		}
+	}
+}
+
+func (board *Board) removeTaskFromShelf(taskId string, shelf TaskShelf) {
+	tasks := board.shelf(shelf)
+
+	for i, it := range *tasks {
+		if it.Task.Id == taskId {
+			*tasks = append((*tasks)[:i:i], (*tasks)[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestRemovingAndRestoringTasks(t *testing.T) {
+	tests := []struct {
+		name string
+		// remove takes "one", which is in the backlog, off the board.
+		remove func(t *testing.T, config *UserConfig, board *Board, task *Task)
+		shelf  TaskShelf
+		event  TaskEventKind
+		// restoredTo is the name of the column the task is put back in.
+		restoredTo string
+	}{
+		{
+			name: "a deleted task goes to the trash",
+			remove: func(t *testing.T, config *UserConfig, board *Board, task *Task) {
+				mustSucceed(t, config.DeleteTask(board.Id, task))
+			},
+			shelf:      TrashShelf,
+			event:      TaskDeleted,
+			restoredTo: "Todo",
+		},
+		{
+			name: "a done task is archived",
+			remove: func(t *testing.T, config *UserConfig, board *Board, task *Task) {
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+				mustSucceed(t, config.ArchiveTask(board.Id, task))
+			},
+			shelf:      ArchiveShelf,
+			event:      TaskArchived,
+			restoredTo: "Done",
+		},
+		{
+			name: "every closed task is archived at once",
+			remove: func(t *testing.T, config *UserConfig, board *Board, task *Task) {
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+
+				count, err := config.ArchiveClosedTasks(board.Id)
+				mustSucceed(t, err)
+				if count != 1 {
+					t.Errorf("Expected 1 task to be archived, got %d", count)
+				}
+			},
+			shelf:      ArchiveShelf,
+			event:      TaskArchived,
+			restoredTo: "Done",
+		},
+		{
+			name: "a task whose column is gone goes back to the backlog",
+			remove: func(t *testing.T, config *UserConfig, board *Board, task *Task) {
+				mustSucceed(t, config.MoveTaskRight(board, task, PlaceOnTop))
+				mustSucceed(t, config.DeleteTask(board.Id, task))
+				mustSucceed(t, config.DeleteColumn(board.Id, board.Columns[1].Id, ""))
+			},
+			shelf:      TrashShelf,
+			event:      TaskDeleted,
+			restoredTo: "Todo",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, board := newTestConfig(t)
+			task := addTestTask(t, config, board, "one")
+			addTestTask(t, config, board, "two")
+
+			test.remove(t, config, board, task)
+
+			if found, _ := findTestTask(board, "one"); found != nil {
+				t.Fatalf("Expected the task to be taken off the board")
+			}
+			removedOpt, shelf := board.GetRemovedTaskById(task.Id)
+			if removedOpt.IsNone() || shelf != test.shelf {
+				t.Fatalf("Expected the task to be in the %s", test.shelf)
+			}
+			if event := task.Events[len(task.Events)-1]; event.Kind != test.event {
+				t.Errorf("Expected the history of the task to end with %s, got %s", test.event, event.Kind)
+			}
+
+			// The shelves are saved along with the board.
+			reopened := openTestBoard(t, reopenConfig(t, config), board.Id)
+			if removedOpt, shelf := reopened.GetRemovedTaskById(task.Id); removedOpt.IsNone() || shelf != test.shelf {
+				t.Errorf("Expected the task to still be in the %s after reopening", test.shelf)
+			}
+
+			mustSucceed(t, config.RestoreTask(board.Id, task.Id))
+
+			restored, column := mustFindTestTask(t, board, "one")
+			if column.Name != test.restoredTo {
+				t.Errorf("Expected the task to be put back in %s, got %s", test.restoredTo, column.Name)
+			}
+			if board.GetTaskPosition(restored) != 0 {
+				t.Errorf("Expected the task to be put back on top of its column")
+			}
+			if removedOpt, _ := board.GetRemovedTaskById(task.Id); removedOpt.IsSome() {
+				t.Errorf("Expected the task to be taken out of the %s", test.shelf)
+			}
+			if event := restored.Events[len(restored.Events)-1]; event.Kind != TaskRestored {
+				t.Errorf("Expected the history of the task to end with its restore, got %s", event.Kind)
+			}
+		})
+	}
+}
+
+func TestArchivingATaskThatIsNotClosed(t *testing.T) {
+	config, board := newTestConfig(t)
+	task := addTestTask(t, config, board, "one")
+
+	err := config.ArchiveTask(board.Id, task)
+	if err == nil {
+		t.Fatalf("Expected archiving a task that isn't done or cancelled to fail")
+	}
+
+	if _, column := mustFindTestTask(t, board, "one"); column.Name != "Todo" {
+		t.Errorf("Expected the task to stay where it was, got %s", column.Name)
+	}
+}
+
+func TestEmptyTrashCanBeUndone(t *testing.T) {
+	config, board := newTestConfig(t)
+	for _, title := range []string{"one", "two", "three"} {
+		task := addTestTask(t, config, board, title)
+		if title != "three" {
+			mustSucceed(t, config.DeleteTask(board.Id, task))
+		}
+	}
+
+	count, err := config.EmptyTrash(board.Id)
+	mustSucceed(t, err)
+	if count != 2 {
+		t.Errorf("Expected 2 tasks to be taken out of the trash, got %d", count)
+	}
+	if len(board.Trash) != 0 {
+		t.Fatalf("Expected the trash to be empty, got %d tasks", len(board.Trash))
+	}
+	if len(openTestBoard(t, reopenConfig(t, config), board.Id).Trash) != 0 {
+		t.Errorf("Expected the trash to be saved empty")
+	}
+
+	// Emptying an empty trash does nothing, so there's nothing to undo for it.
+	count, err = config.EmptyTrash(board.Id)
+	mustSucceed(t, err)
+	if count != 0 {
+		t.Errorf("Expected nothing to be taken out of an empty trash, got %d", count)
+	}
+
+	undone, err := config.Undo(board)
+	mustSucceed(t, err)
+	if undone.IsNone() || undone.Unwrap().Description != "Empty the trash" {
+		t.Fatalf("Expected emptying the trash to be undone")
+	}
+
+	if len(board.Trash) != 2 || board.Trash[0].Task.Title != "one" || board.Trash[1].Task.Title != "two" {
+		t.Fatalf("Expected the tasks to be put back in the trash in their order")
+	}
+	for _, it := range board.Trash {
+		if it.Column != board.Columns[0].Id {
+			t.Errorf("Expected \"%s\" to keep the column it was taken from", it.Task.Title)
+		}
+	}
+	if found, _ := findTestTask(board, "one"); found != nil {
+		t.Errorf("Expected the task to stay off the board")
+	}
+
+	reopened := openTestBoard(t, reopenConfig(t, config), board.Id)
+	if len(reopened.Trash) != 2 {
+		t.Errorf("Expected the trash to be saved with the tasks back in it, got %d tasks", len(reopened.Trash))
+	}
+
+	mustSucceed(t, config.RestoreTask(board.Id, board.Trash[0].Task.Id))
+	mustFindTestTask(t, board, "one")
+}
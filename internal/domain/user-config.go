@@ -1,87 +1,122 @@
 package domain
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/gizak/termui/v3"
+	"github.com/google/uuid"
 	"github.com/okira-e/gotasks/internal/opt"
 	"github.com/okira-e/gotasks/internal/utils"
 )
 
 /*
+config.json only holds the global settings and an index of the boards:
 {
+	"primary_color": 4,
 	"boards": [
 		{
+			"id": "0b5c6e1e-5f7a-4a8e-9b43-2f1b0c3f0d11",
 			"name": "masa",
-			"dir": "/Users/omarrafat/Boards/masa",
-			"board": {
-				"columns": ["Todo", "Open", "Closed"],
-				"tasks": {
-					"Todo": [
-						{
-							"title": "Lorem",
-							"description": "Some optional Lorem Epison"
-						},
-						{
-							"title": "Lorem",
-							"description": "Some optional Lorem Epison"
-						}
-					],
-					"Open": [
-						{
-							"title": "Lorem",
-							"description": "Some optional Lorem Epison"
-						}
-					],
-					"Closed": [
-						{
-							"title": "Lorem",
-							"description": "Some optional Lorem Epison"
-						}
-					]
-				}
-			}
+			"dir": "/Users/omarrafat/Boards/masa"
+		}
+	],
+	"templates": [
+		{
+			"name": "bug",
+			"title": "Bug: {title}",
+			"description": "Steps to reproduce:\n\nExpected:\n\nActual:",
+			"labels": ["bug"],
+			"checklist": ["Reproduce", "Fix", "Add a regression test"]
 		}
 	]
 }
+
+Every board is then stored in its own file under boards/<id>.json:
+{
+	"id": "0b5c6e1e-5f7a-4a8e-9b43-2f1b0c3f0d11",
+	"name": "masa",
+	"dir": "/Users/omarrafat/Boards/masa",
+	"columns": [
+		{"id": "5d0e8f3a-...", "name": "Todo", "role": "backlog"},
+		{"id": "9b1c2d4e-...", "name": "Open", "wip_limit": 3, "role": "active"},
+		{"id": "e7f6a5b4-...", "name": "Closed", "role": "done"}
+	],
+	"tasks": {
+		"5d0e8f3a-...": [
+			{
+				"title": "Lorem",
+				"description": "Some optional Lorem Epison"
+			}
+		],
+		"9b1c2d4e-...": [],
+		"e7f6a5b4-...": []
+	}
+}
 */
 
 
 type UserConfig struct {
+	// SchemaVersion is the version of the persisted format. See migrations.go.
+	SchemaVersion	int				`json:"schema_version"`
 	PrimaryColor 	termui.Color	`json:"primary_color"`
-	Boards 			[]*Board 		`json:"boards"`
+	// Boards is the index of all the boards. The content of each board lives in
+	// its own file and is only read when the board is asked for.
+	Boards 			[]*BoardEntry 	`json:"boards"`
+	// Templates are the global templates tasks can start from, on every board.
+	Templates		[]*TaskTemplate	`json:"templates,omitempty"`
+
+	// store is where the config and the boards are read from and saved to.
+	store			Store
+	// loadedBoards caches the boards that were read from the store, keyed by board ID.
+	loadedBoards	map[string]*Board
+	// revisions holds the store revision of every loaded board as of the last
+	// time this config read or saved it. See HasBoardChanged.
+	revisions		map[string]string
+	// baseBoards holds a copy of every loaded board as of the last time this config
+	// read or saved it. Changes saved by other processes are merged against it.
+	baseBoards		map[string]*Board
+	// histories caches the undo history of the boards, keyed by board ID.
+	histories		map[string]*History
+}
+
+// BoardEntry is the record kept in the boards index for every board.
+type BoardEntry struct {
+	Id		string	`json:"id"`
+	Name	string	`json:"name"`
+	Dir		string	`json:"dir"`
 }
 
 // DoesUserConfigExist checks if a user config has already be generated for this user.
 func DoesUserConfigExist() (bool, error) {
-	filePath, err := GetConfigFilePathBasedOnOS()
+	store, err := GetStoreBasedOnEnv()
 	if err != nil {
 		return false, err
 	}
 	
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return false, nil
-	}
-
-	return true, nil
+	return store.Exists()
 }
 
-// SetupUserConfig creates a new default config and writes it to disk.
+// SetupUserConfig creates a new default config and saves it to the store.
 // It returns a pointer to the new config.
 func SetupUserConfig() (*UserConfig, error) {
-	config := NewDefaultUserConfig()
-	
-	err := createDefaultConfigFiles()
+	store, err := GetStoreBasedOnEnv()
 	if err != nil {
-		return nil, fmt.Errorf("Faield to create the paret config folder. %s", err)
+		return nil, err
 	}
 	
-	err = config.writeToDisk()
+	return SetupUserConfigInStore(store)
+}
+
+// SetupUserConfigInStore is SetupUserConfig for a specific store.
+func SetupUserConfigInStore(store Store) (*UserConfig, error) {
+	config := NewDefaultUserConfig()
+	config.store = store
+	
+	err := config.saveConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -89,62 +124,123 @@ func SetupUserConfig() (*UserConfig, error) {
 	return config, nil
 }
 
-// GetUserConfig reads the user config file and returns a pointer 
-// to a UserConfig object.
+// GetUserConfig reads the user config from the store picked by the environment
+// and returns a pointer to a UserConfig object. Boards are not read here, see GetBoard.
 func GetUserConfig() (*UserConfig, error) {
-	userConfig := new(UserConfig)
-
-	filePath, err := GetConfigFilePathBasedOnOS()
+	store, err := GetStoreBasedOnEnv()
 	if err != nil {
 		return nil, err
 	}
+	
+	return GetUserConfigFromStore(store)
+}
 
-	fileContent, err := os.ReadFile(filePath)
+// GetUserConfigFromStore is GetUserConfig for a specific store. Whatever is in
+// the store is migrated to the current schema version first.
+func GetUserConfigFromStore(store Store) (*UserConfig, error) {
+	err := migrateStoreIfNeeded(store)
 	if err != nil {
 		return nil, err
 	}
 	
-	err = json.Unmarshal(fileContent, &userConfig)
+	userConfig, err := store.LoadConfig()
 	if err != nil {
 		return nil, err
 	}
+	
+	userConfig.store = store
+	if userConfig.loadedBoards == nil {
+		userConfig.loadedBoards = map[string]*Board{}
+	}
+	if userConfig.revisions == nil {
+		userConfig.revisions = map[string]string{}
+	}
+	if userConfig.baseBoards == nil {
+		userConfig.baseBoards = map[string]*Board{}
+	}
+	if userConfig.histories == nil {
+		userConfig.histories = map[string]*History{}
+	}
+	for _, board := range userConfig.loadedBoards {
+		userConfig.rememberBoard(board)
+	}
 
 	return userConfig, nil
 }
 
+// migrateStoreIfNeeded runs the pending migrations on the store, if it has a config
+// saved and it's a store that can be migrated.
+func migrateStoreIfNeeded(store Store) error {
+	migratableStore, ok := store.(documentStore)
+	if !ok {
+		return nil
+	}
+	
+	exists, err := store.Exists()
+	if err != nil || !exists {
+		return err
+	}
+	
+	unlock, err := store.Lock()
+	if err != nil {
+		return fmt.Errorf("Failed to lock the store for migrating it. %s", err)
+	}
+	defer unlock()
+	
+	return migrateStore(migratableStore)
+}
+
 func NewDefaultUserConfig() *UserConfig {
 	ret := new(UserConfig)
 	
+	ret.SchemaVersion = CurrentSchemaVersion
 	ret.PrimaryColor = termui.ColorBlue
+	ret.loadedBoards = map[string]*Board{}
+	ret.revisions = map[string]string{}
+	ret.baseBoards = map[string]*Board{}
+	ret.histories = map[string]*History{}
 	
 	return ret
 }
 
-// CreateBoard adds a new board to the config.
-func (self *UserConfig) CreateBoard(boardName string, dirPath string) error {
+// CreateBoard adds a new board for the given directory to the config. Board names
+// are unique, so the name is disambiguated if another board already has it.
+func (self *UserConfig) CreateBoard(boardName string, dirPath string) (*Board, error) {
 	board := new(Board)
 	
-	board.Name = boardName
+	board.Id = uuid.New().String()
+	board.SchemaVersion = CurrentSchemaVersion
+	board.Name = disambiguateBoardName(boardName, dirPath, func(name string) bool {
+		entryOpt := self.GetBoardEntry(name)
+		return entryOpt.IsSome()
+	})
 	board.Dir = dirPath
-	board.Columns = []string{
-		"Todo",
-		"In Progress",
-		"Done",
-	}
+	board.TaskKeyPrefix = DefaultTaskKeyPrefix(board.Name)
+	board.Columns = defaultColumns()
 	board.Tasks = map[string][]*Task{}
 	
-	// Add the newly created board to the config.
-	self.Boards = append(self.Boards, board)
-	self.writeToDisk()
+	err := self.store.SaveBoard(board)
+	if err != nil {
+		return nil, err
+	}
 	
-	return nil
+	// Add the newly created board to the index.
+	self.Boards = append(self.Boards, board.entry())
+	self.loadedBoards[board.Id] = board
+	
+	err = self.saveConfig()
+	if err != nil {
+		return nil, err
+	}
+	
+	return board, nil
 }
 
-// AddTask adds a new task to the left most column (idealy called Backlog).
-func (self *UserConfig) AddTask(boardName string, task *Task) error {
+// AddTask adds a new task to the backlog column of the board.
+func (self *UserConfig) AddTask(boardId string, task *Task) error {
 	utils.SaveLog(utils.Debug, "Adding task", map[string]any{"task": task})
 	
-	boardOpt := self.GetBoard(boardName)
+	boardOpt := self.GetBoardById(boardId)
 	if boardOpt.IsNone() {
 		return errors.New("Couldn't find the board while trying to add a task")
 	}
@@ -155,24 +251,39 @@ func (self *UserConfig) AddTask(boardName string, task *Task) error {
 		return errors.New("No columns found to add this task to.")
 	}
 	
-	columnName := board.Columns[0]
+	columnId := board.GetBacklogColumn().Id
 	
-	board.Tasks[columnName] = append(board.Tasks[columnName], task)
+	return self.runCommand(board, "Add \"" + task.Title + "\"", []string{task.Id}, func() error {
+		board.Tasks[columnId] = append(board.Tasks[columnId], task)
+		return nil
+	})
+}
+
+// EditTask changes the task on the board through the given function, like setting
+// its title or its priority.
+func (self *UserConfig) EditTask(boardId string, task *Task, edit func(task *Task)) error {
+	utils.SaveLog(utils.Debug, "Editing a task", map[string]any{"task": task})
 	
-	err := self.UpdateBoard(board)
-	if err != nil {
-		return err
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return errors.New("Couldn't find the board while trying to edit a task")
 	}
 	
-	return nil
+	board := boardOpt.Unwrap()
+	
+	return self.runCommand(board, "Edit \"" + task.Title + "\"", []string{task.Id}, func() error {
+		edit(task)
+		return nil
+	})
 }
 
-func (self *UserConfig) DeleteTask(boardName string, task *Task) error {
+// DeleteTask moves the task to the trash of the board, where it can be restored from.
+func (self *UserConfig) DeleteTask(boardId string, task *Task) error {
 	utils.SaveLog(utils.Debug, "Deleting a task", map[string]any{"task": task})
 	
-	boardOpt := self.GetBoard(boardName)
+	boardOpt := self.GetBoardById(boardId)
 	if boardOpt.IsNone() {
-		return errors.New("Couldn't find the board while trying to add a task")
+		return errors.New("Couldn't find the board while trying to delete a task")
 	}
 	
 	board := boardOpt.Unwrap()
@@ -180,107 +291,239 @@ func (self *UserConfig) DeleteTask(boardName string, task *Task) error {
 	// Get the column for the task
 	column, columnIndex := board.GetColumnForTask(task)
 	if columnIndex < 0 {
-		utils.SaveLog(
-			utils.Error, 
-			"Couldn't Find column to the task while deleting it", 
-			map[string]any{"task": task},
-		)
-		
-		log.Fatalln("Couldn't Find column to the task while deleting it")
+		return errors.New("Couldn't find the column of the task while deleting it")
 	}
 	
-	// 
-	// Find and remove the task from this column
-	// 
+	return self.runCommand(board, "Delete \"" + task.Title + "\"", []string{task.Id}, func() error {
+		// 
+		// Find and remove the task from this column
+		// 
+		
+		for i, it := range board.Tasks[column.Id] {
+			if it == task {
+				board.Tasks[column.Id] = append(board.Tasks[column.Id][:i], board.Tasks[column.Id][i+1:]...)
+				break
+			}
+		}
+		
+		board.Trash = append(board.Trash, newRemovedTask(task, column.Id))
+		
+		return nil
+	})
+}
+
+// GetBoardEntry searches the index for a board with the given name. Names are only
+// labels for showing and picking boards, boards are identified by their IDs.
+func (self *UserConfig) GetBoardEntry(boardName string) opt.Option[*BoardEntry] {
+	for _, it := range self.Boards {
+		if it.Name == boardName {
+			return opt.Some(it)
+		}
+	}
 	
-	for i, it := range board.Tasks[column] {
-		if it == task {
-			board.Tasks[column] = append(board.Tasks[column][:i], board.Tasks[column][i+1:]...)
+	return opt.None[*BoardEntry]()
+}
+
+// GetBoardEntryById searches the index for the board with the given ID.
+func (self *UserConfig) GetBoardEntryById(boardId string) opt.Option[*BoardEntry] {
+	for _, it := range self.Boards {
+		if it.Id == boardId {
+			return opt.Some(it)
 		}
 	}
 	
-	err := self.UpdateBoard(board)
+	return opt.None[*BoardEntry]()
+}
+
+// GetBoard searches the config for a board with the given name and reads it
+// from disk if it wasn't read already. A board that fails to load is logged and
+// treated as missing. Use LoadBoard to get the error itself.
+func (self *UserConfig) GetBoard(boardName string) opt.Option[*Board] {
+	return self.getBoardForEntry(self.GetBoardEntry(boardName))
+}
+
+// GetBoardById is GetBoard by the board's ID.
+func (self *UserConfig) GetBoardById(boardId string) opt.Option[*Board] {
+	return self.getBoardForEntry(self.GetBoardEntryById(boardId))
+}
+
+func (self *UserConfig) getBoardForEntry(entryOpt opt.Option[*BoardEntry]) opt.Option[*Board] {
+	if entryOpt.IsNone() {
+		return opt.None[*Board]()
+	}
+	
+	entry := entryOpt.Unwrap()
+	
+	board, err := self.LoadBoard(entry)
 	if err != nil {
-		return err
+		utils.SaveLog(utils.Error, "Failed to load a board", map[string]any{"board": entry.Name, "error": err.Error()})
+		return opt.None[*Board]()
 	}
 	
-	return nil
+	return opt.Some(board)
 }
 
-// GetBoard searches the config for a board with the given name.
-func (self *UserConfig) GetBoard(boardName string) opt.Option[*Board] {
-	for _, it := range self.Boards {
-		if it.Name == boardName {
-			return opt.Some(it)
+// LoadBoard returns the board for the given index entry, reading it from the
+// store only the first time it's asked for.
+func (self *UserConfig) LoadBoard(entry *BoardEntry) (*Board, error) {
+	if board, ok := self.loadedBoards[entry.Id]; ok {
+		return board, nil
+	}
+	
+	board, err := self.store.LoadBoard(entry.Id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the board \"%s\". %s", entry.Name, err)
+	}
+	
+	board.adoptOrphanedTasks()
+	
+	self.loadedBoards[entry.Id] = board
+	self.rememberBoard(board)
+	
+	return board, nil
+}
+
+// FindTask searches every board for the task with the given key or ID. A prefix of
+// the ID is enough as long as only one task starts with it. It returns the board the task
+// is on along with the task.
+func (self *UserConfig) FindTask(taskId string) (*Board, *Task, error) {
+	var foundBoard *Board
+	var foundTask *Task
+	
+	for _, entry := range self.Boards {
+		board, err := self.LoadBoard(entry)
+		if err != nil {
+			utils.SaveLog(utils.Error, "Failed to load a board while searching for a task", map[string]any{"board": entry.Name, "error": err.Error()})
+			continue
+		}
+		
+		for _, column := range board.Columns {
+			for _, task := range board.Tasks[column.Id] {
+				if task.Id == taskId || board.matchesTaskKey(task, taskId) {
+					return board, task, nil
+				}
+				
+				if taskId == "" || !strings.HasPrefix(task.Id, taskId) {
+					continue
+				}
+				
+				if foundTask != nil {
+					return nil, nil, fmt.Errorf("More than one task has an ID that starts with \"%s\"", taskId)
+				}
+				
+				foundBoard = board
+				foundTask = task
+			}
 		}
 	}
 	
-	return opt.None[*Board]()
+	if foundTask == nil {
+		return nil, nil, fmt.Errorf("Couldn't find a task with the key or ID \"%s\"", taskId)
+	}
+	
+	return foundBoard, foundTask, nil
 }
 
-// UpdateBoard finds and updates the given board in the user config.
+// UpdateBoard saves the given board to the store. The store is locked while doing so,
+// and if another process saved the board since it was last read, their changes are
+// merged in per task first. The merge happens in place on the given board.
 func (self *UserConfig) UpdateBoard(board *Board) error {
-	for i, it := range self.Boards {
-		if it.Name == board.Name {
-			self.Boards[i] = board
-		}
+	self.loadedBoards[board.Id] = board
+	
+	unlock, err := self.store.Lock()
+	if err != nil {
+		return fmt.Errorf("Failed to lock the store on board update. %s", err)
 	}
+	defer unlock()
 	
-	err := self.writeToDisk()
+	err = self.mergeOutsideChanges(board)
 	if err != nil {
-		log.Fatalf("Failed to write to the user config on board update. %s", err)
+		return fmt.Errorf("Failed to merge the changes saved by another process. %s", err)
 	}
 	
+	err = self.store.SaveBoard(board)
+	if err != nil {
+		return fmt.Errorf("Failed to write the board on board update. %s", err)
+	}
+	self.rememberBoard(board)
+	
 	return nil
 }
 
-// AddColumnToBoard adds a column to the board of the board with the given name.
-func (self *UserConfig) AddColumnToBoard(boardName string, columnName string) error {
-	boardOpt := self.GetBoard(boardName)
-	board := boardOpt.Expect("Failed to find board whole adding a column.")
-
-	board.Columns = append(board.Columns, columnName)
+// mergeOutsideChanges merges into the given board whatever another process saved
+// to it since this config last read or saved it. The store should be locked.
+func (self *UserConfig) mergeOutsideChanges(board *Board) error {
+	changed, err := self.HasBoardChanged(board)
+	if err != nil || !changed {
+		return err
+	}
 	
-	err := self.UpdateBoard(board)
+	theirs, err := self.store.LoadBoard(board.Id)
 	if err != nil {
 		return err
 	}
 	
+	base, ok := self.baseBoards[board.Id]
+	if !ok || base == nil {
+		// Without knowing what the board looked like before, there's nothing to merge against.
+		utils.SaveLog(utils.Warn, "Overwriting the changes saved by another process", map[string]any{"board": board.Name})
+		return nil
+	}
+	
+	utils.SaveLog(utils.Info, "Merging the changes saved by another process", map[string]any{"board": board.Name})
+	
+	mergeBoards(base, board, theirs)
+	
 	return nil
 }
 
-// writeToDisk writes or creates the config files with the provided user config.
-func (self UserConfig) writeToDisk() error { 
-	filePath, err := GetConfigFilePathBasedOnOS()
-	if err != nil {
-		return fmt.Errorf("Failed to get the config file. %s", err)
-	}
-
-	file, err := os.Create(filePath)
+// saveConfig saves the global settings and the boards index to the store.
+// Boards themselves are saved by UpdateBoard.
+func (self *UserConfig) saveConfig() error {
+	unlock, err := self.store.Lock()
 	if err != nil {
-		return fmt.Errorf("Failed to create config file.. %s", err)
+		return fmt.Errorf("Failed to lock the store on saving the config. %s", err)
+	}
+	defer unlock()
+	
+	// Keep the boards another process added to the index since it was read.
+	theirs, err := self.store.LoadConfig()
+	if err == nil {
+		for _, theirEntry := range theirs.Boards {
+			found := false
+			for _, it := range self.Boards {
+				if it.Id == theirEntry.Id {
+					found = true
+					break
+				}
+			}
+			
+			if !found {
+				self.Boards = append(self.Boards, theirEntry)
+			}
+		}
 	}
-	defer file.Close()
-
-	fileContent, err := json.MarshalIndent(self, "", "\t")
+	
+	err = self.store.SaveConfig(self)
 	if err != nil {
-		return fmt.Errorf("Failed to marshal user config. %s", err)
+		return err
 	}
-
-	_, err = file.Write(fileContent)
-	if err != nil {
-		return fmt.Errorf("Failed to write to disk. %s", err)
+	
+	// Saving the config changes the revision of every board.
+	for boardId := range self.loadedBoards {
+		self.rememberRevision(boardId)
 	}
 	
 	return nil
 }
 
 // MoveTaskRight moves the task to the right column of the one its currently on and removes it
-// from the old column.
-func (self *UserConfig) MoveTaskRight(board *Board, task *Task) error {
+// from the old column. It lands on top of the new column or at its bottom, going by the placement.
+// It returns a WipLimitError if the board's WIP limits are strict and the column is full.
+func (self *UserConfig) MoveTaskRight(board *Board, task *Task, placement TaskPlacement) error {
 	oldColumn, i := board.GetColumnForTask(task)
 	if i == -1 {
-		log.Fatalf("Failed to find the column for task on scrolling to bottom.")
+		return errors.New("Couldn't find the column of the task while moving it")
 	}
 	
 	nextColumnIndex := i + 1
@@ -290,30 +533,28 @@ func (self *UserConfig) MoveTaskRight(board *Board, task *Task) error {
 	
 	nextColumn := board.Columns[nextColumnIndex]
 	
-	board.Tasks[nextColumn] = append(board.Tasks[nextColumn], task)
-	
-	// Remove task from previous column
-	for i, it := range board.Tasks[oldColumn] {
-		if it == task {
-			board.Tasks[oldColumn] = append(board.Tasks[oldColumn][:i], board.Tasks[oldColumn][i+1:]...)
-			break
-		}
-	}
-
-	err := self.UpdateBoard(board)
+	err := board.checkWipLimit(nextColumn, 1)
 	if err != nil {
 		return err
 	}
 	
-	return nil
+	// Moving a task forward while it's still blocked is allowed, but it's likely a
+	// mistake. Callers that can ask first should check GetOpenBlockers before.
+	openBlockers := board.GetOpenBlockers(task)
+	if len(openBlockers) > 0 {
+		utils.SaveLog(utils.Warn, "Moving a task forward while it's still blocked", map[string]any{"task": task.Title, "openBlockers": len(openBlockers)})
+	}
+	
+	return self.moveTaskToColumn(board, task, oldColumn, nextColumn, placement)
 }
 
 // MoveTaskLeft moves the task to the left column of the one its currently on and removes it
-// from the old column.
-func (self *UserConfig) MoveTaskLeft(board *Board, task *Task) error {
+// from the old column. It lands on top of the new column or at its bottom, going by the placement.
+// It returns a WipLimitError if the board's WIP limits are strict and the column is full.
+func (self *UserConfig) MoveTaskLeft(board *Board, task *Task, placement TaskPlacement) error {
 	oldColumn, i := board.GetColumnForTask(task)
 	if i == -1 {
-		log.Fatalf("Failed to find the column for task on scrolling to bottom.")
+		return errors.New("Couldn't find the column of the task while moving it")
 	}
 	
 	prevColumnIndex := i - 1
@@ -323,49 +564,125 @@ func (self *UserConfig) MoveTaskLeft(board *Board, task *Task) error {
 	
 	prevColumn := board.Columns[prevColumnIndex]
 	
-	board.Tasks[prevColumn] = append(board.Tasks[prevColumn], task)
-	
-	// Remove task from previous column
-	for i, it := range board.Tasks[oldColumn] {
-		if it == task {
-			board.Tasks[oldColumn] = append(board.Tasks[oldColumn][:i], board.Tasks[oldColumn][i+1:]...)
-			break
-		}
-	}
-
-	err := self.UpdateBoard(board)
+	err := board.checkWipLimit(prevColumn, 1)
 	if err != nil {
 		return err
 	}
 	
-	return nil
+	return self.moveTaskToColumn(board, task, oldColumn, prevColumn, placement)
+}
+
+// moveTaskToColumn moves the task from its column to the other one.
+func (self *UserConfig) moveTaskToColumn(board *Board, task *Task, oldColumn *Column, column *Column, placement TaskPlacement) error {
+	return self.moveTasksToColumn(board, "Move \"" + task.Title + "\" to " + column.Name, []*Task{task}, oldColumn, column, placement, nil)
+}
+
+// moveTasksToColumn moves the tasks from their column to the other one in a single
+// command, one after the other. A recurring task moved to a done column comes back as
+// part of the same move, so undoing the move takes its next occurrence away too. The
+// given function, if any, makes the rest of the change to the board after the move.
+func (self *UserConfig) moveTasksToColumn(board *Board, description string, tasks []*Task, oldColumn *Column, column *Column, placement TaskPlacement, then func()) error {
+	taskIds := []string{}
+	nextOccurrences := make([]*Task, len(tasks))
+	
+	for i, task := range tasks {
+		taskIds = append(taskIds, task.Id)
+		
+		if column.Role == DoneRole && task.Recurrence != nil && task.NextOccurrenceId == "" {
+			nextOccurrences[i] = task.newNextOccurrence()
+			taskIds = append(taskIds, nextOccurrences[i].Id)
+		}
+	}
+	
+	return self.runCommand(board, description, taskIds, func() error {
+		for i, task := range tasks {
+			board.removeTaskFromColumn(task, oldColumn.Id)
+			board.insertTask(column.Id, task, placement)
+			
+			if nextOccurrences[i] != nil {
+				board.addNextOccurrence(task, nextOccurrences[i])
+			}
+		}
+		
+		if then != nil {
+			then()
+		}
+		
+		return nil
+	})
 }
 
 type Board struct {
+	// SchemaVersion is the version of the persisted format. See migrations.go.
+	SchemaVersion int  `json:"schema_version"`
+	Id      string   `json:"id"`
 	Name    string   `json:"name"`
 	Dir     string   `json:"dir"`
-	Columns []string `json:"columns"`
-	// Tasks are the individual cards on the board representing a task.
+	Columns []*Column `json:"columns"`
+	// TaskKeyPrefix is what the keys of the tasks of the board start with, like "API"
+	// in "API-42".
+	TaskKeyPrefix string `json:"task_key_prefix"`
+	// LastTaskNumber is the number the last task added to the board got. It only goes up.
+	LastTaskNumber int `json:"last_task_number"`
+	// StrictWipLimits is set if moving a task over the WIP limit of a column is refused
+	// rather than only confirmed first.
+	StrictWipLimits bool `json:"strict_wip_limits,omitempty"`
+	// Swimlanes is what the tasks of the board are split into horizontal lanes by.
+	// The board has no lanes if it's empty.
+	Swimlanes SwimlaneGrouping `json:"swimlanes,omitempty"`
+	// Templates are the templates the tasks of the board can start from, along with
+	// the global ones. See templates.go.
+	Templates []*TaskTemplate `json:"templates,omitempty"`
+	// ShowTaskAges is set if the cards of the board show how old their tasks are.
+	ShowTaskAges bool `json:"show_task_ages,omitempty"`
+	// Tasks are the individual cards on the board representing a task, keyed by the
+	// ID of their column.
 	Tasks map[string][]*Task `json:"tasks"`
+	// Archive holds the done tasks that were taken off the board to keep them around.
+	Archive []*RemovedTask `json:"archive,omitempty"`
+	// Trash holds the deleted tasks until the trash is emptied.
+	Trash []*RemovedTask `json:"trash,omitempty"`
 }
 
-// GetColumnForTask returns the name and the index of the column that this task belongs to. 
-// It returns -1 as the index if didn't find the column.
-func (board *Board) GetColumnForTask(task *Task) (string, int) {
-	for i, columnName := range board.Columns {
-		for _, it := range board.Tasks[columnName] {
+// entry returns the index record for this board.
+func (board *Board) entry() *BoardEntry {
+	return &BoardEntry{
+		Id:   board.Id,
+		Name: board.Name,
+		Dir:  board.Dir,
+	}
+}
+
+// GetColumnForTask returns the column that this task belongs to and its index. 
+// It returns nil and -1 as the index if didn't find the column.
+func (board *Board) GetColumnForTask(task *Task) (*Column, int) {
+	for i, column := range board.Columns {
+		for _, it := range board.Tasks[column.Id] {
 			if it == task {
-				return columnName, i
+				return column, i
 			}
 		}
 	}
 	
-	return "", -1
+	return nil, -1
+}
+
+// GetTaskById searches every column of the board for the task with the given ID.
+func (board *Board) GetTaskById(taskId string) opt.Option[*Task] {
+	for _, column := range board.Columns {
+		for _, it := range board.Tasks[column.Id] {
+			if it.Id == taskId {
+				return opt.Some(it)
+			}
+		}
+	}
+	
+	return opt.None[*Task]()
 }
 
 func (board *Board) IsEmpty() bool {
 	for _, column := range board.Columns {
-		if len(board.Tasks[column]) != 0 {
+		if len(board.Tasks[column.Id]) != 0 {
 			return false
 		}
 	}
@@ -444,36 +761,3 @@ func doesConfigFileExists() (bool, error) {
 
 	return true, nil
 }
-
-// createDefaultConfigFiles creates the config file.
-func createDefaultConfigFiles() error {
-	filePath, err := GetConfigFilePathBasedOnOS()
-	if err != nil {
-		return err
-	}
-
-	// Create the directory.
-	dirPath := filePath[:len(filePath)-len("/config.json")]
-	err = os.MkdirAll(dirPath, os.ModePerm)
-	if err != nil {
-		return err
-	}
-
-	// Create the file inside the directory.
-	file, err := os.Create(filePath)
-	defer file.Close()
-
-	defaultConfig := NewDefaultUserConfig()
-	
-	defaultConfigJSON, err := json.Marshal(defaultConfig)
-	if err != nil {
-		return err
-	}
-	
-	_, err = file.Write([]byte(defaultConfigJSON))
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
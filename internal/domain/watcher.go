@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// WatchBoard polls the store every interval and sends on the returned channel
+// whenever the revision of the config or the given board changes. That includes
+// saves done by this process, use HasBoardChanged to tell the two apart.
+// The channel never closes; a send is skipped if the previous one wasn't received yet.
+func (self *UserConfig) WatchBoard(board *Board, interval time.Duration) <-chan struct{} {
+	ret := make(chan struct{}, 1)
+
+	// Only the store and the board ID are used from the goroutine. Neither changes
+	// once the board is loaded, so nothing here races with the caller.
+	store := self.store
+	boardId := board.Id
+
+	go func() {
+		lastRevision, _ := store.Revision(boardId)
+
+		for range time.Tick(interval) {
+			revision, err := store.Revision(boardId)
+			if err != nil || revision == lastRevision {
+				continue
+			}
+
+			lastRevision = revision
+
+			select {
+			case ret <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return ret
+}
+
+// HasBoardChanged reports if the config or the given board were saved by someone
+// else since this config last read or saved them.
+func (self *UserConfig) HasBoardChanged(board *Board) (bool, error) {
+	revision, err := self.store.Revision(board.Id)
+	if err != nil {
+		return false, err
+	}
+
+	return revision != self.revisions[board.Id], nil
+}
+
+// ReloadBoard reads the config and the given board again from the store. The
+// board is updated in place so everyone holding a pointer to it sees the new
+// content, but its tasks are new pointers.
+func (self *UserConfig) ReloadBoard(board *Board) error {
+	config, err := self.store.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	freshBoard, err := self.store.LoadBoard(board.Id)
+	if err != nil {
+		return err
+	}
+
+	utils.SaveLog(utils.Info, "Reloading a board that was changed outside", map[string]any{"board": board.Name})
+
+	self.PrimaryColor = config.PrimaryColor
+	self.Boards = config.Boards
+
+	*board = *freshBoard
+	board.adoptOrphanedTasks()
+	self.loadedBoards[board.Id] = board
+	self.rememberBoard(board)
+
+	return nil
+}
+
+// rememberBoard records the board as this config has seen it in the store, both
+// its revision and a copy of its content to merge outside changes against.
+func (self *UserConfig) rememberBoard(board *Board) {
+	self.rememberRevision(board.Id)
+	self.baseBoards[board.Id] = board.clone()
+}
+
+// rememberRevision records the current store revision of the board as the one
+// this config has seen.
+func (self *UserConfig) rememberRevision(boardId string) {
+	revision, err := self.store.Revision(boardId)
+	if err != nil {
+		utils.SaveLog(utils.Error, "Failed to get the revision of a board", map[string]any{"boardId": boardId, "error": err.Error()})
+		return
+	}
+
+	self.revisions[boardId] = revision
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+)
+
+// WipLimitError is returned when moving a task would put more tasks in a column than
+// its work-in-progress limit allows, on a board whose limits are strict.
+type WipLimitError struct {
+	Column string
+	Limit  int
+}
+
+func (err *WipLimitError) Error() string {
+	return fmt.Sprintf("%s is at its WIP limit of %d", err.Column, err.Limit)
+}
+
+// IsOverWipLimit reports if the column has more tasks than its limit allows.
+func (board *Board) IsOverWipLimit(column *Column) bool {
+	return column.WipLimit > 0 && len(board.Tasks[column.Id]) > column.WipLimit
+}
+
+// WouldBreakWipLimit reports if adding another task to the column would put it over
+// its limit.
+func (board *Board) WouldBreakWipLimit(column *Column) bool {
+	return column.WipLimit > 0 && len(board.Tasks[column.Id])+1 > column.WipLimit
+}
+
+// checkWipLimit returns a WipLimitError if moving the given number of tasks into the
+// column would break its limit and the limits of the board are strict.
+func (board *Board) checkWipLimit(column *Column, count int) error {
+	if !board.StrictWipLimits || column.WipLimit == 0 || len(board.Tasks[column.Id])+count <= column.WipLimit {
+		return nil
+	}
+
+	return &WipLimitError{
+		Column: column.Name,
+		Limit:  column.WipLimit,
+	}
+}
+
+// SetWipLimit sets the work-in-progress limit of the column with the given ID. A limit
+// of 0 removes it.
+func (self *UserConfig) SetWipLimit(boardId string, columnId string, limit int) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return fmt.Errorf("Couldn't find the board while trying to set a WIP limit")
+	}
+
+	board := boardOpt.Unwrap()
+
+	columnOpt := board.GetColumnById(columnId)
+	if columnOpt.IsNone() {
+		return fmt.Errorf("Couldn't find the column to set the WIP limit of")
+	}
+
+	if limit < 0 {
+		return fmt.Errorf("A WIP limit can't be negative")
+	}
+
+	columnOpt.Unwrap().WipLimit = limit
+
+	return self.UpdateBoard(board)
+}
+
+// SetStrictWipLimits sets if moving a task over the WIP limit of a column is refused
+// (strict) or only confirmed first (soft).
+func (self *UserConfig) SetStrictWipLimits(boardId string, strict bool) error {
+	boardOpt := self.GetBoardById(boardId)
+	if boardOpt.IsNone() {
+		return fmt.Errorf("Couldn't find the board while trying to set how strict its WIP limits are")
+	}
+
+	board := boardOpt.Unwrap()
+	board.StrictWipLimits = strict
+
+	return self.UpdateBoard(board)
+}
@@ -3,11 +3,13 @@ package ui
 import (
 	"errors"
 	"os"
+	"time"
 
 	"github.com/gizak/termui/v3"
 	"github.com/okira-e/gotasks/internal/domain"
 	"github.com/okira-e/gotasks/internal/ui/components"
 	"github.com/okira-e/gotasks/internal/ui/types"
+	"github.com/okira-e/gotasks/internal/utils"
 	"github.com/okira-e/gotasks/internal/vars"
 )
 
@@ -20,6 +22,13 @@ const (
 	CreateTaskDescription
 )
 
+// BoardChangedEvent is raised in the event loop when the board, or the config,
+// is saved by another process. It's numbered away from the event types of termui.
+const BoardChangedEvent termui.EventType = 100
+
+// boardWatchInterval is how often the store is checked for changes made outside.
+const boardWatchInterval = time.Second
+
 type Component interface {
 	GetAllDrawableWidgets() []termui.Drawable
 	Draw()
@@ -29,7 +38,8 @@ type Component interface {
 // rendering anything to the screen.
 type App struct {
 	userConfig                  	*domain.UserConfig
-	boardName                   	string
+	boardId                     	string
+	board							*domain.Board
 	window							types.Window
 	// theme could be "dark" or "light". Is set through an environment variable.
 	theme                       	string
@@ -38,23 +48,36 @@ type App struct {
 	tasksView						*components.TasksViewComponent
 	confirmationPopup				*components.ConfirmationComponent
 	searchDialogPopup				*components.SearchDialogPopupComponent
+	notificationPopup				*components.NotificationComponent
+	taskDetailsPopup				*components.TaskDetailsComponent
+	removedTasksBrowser				*components.RemovedTasksBrowserComponent
+	columnsEditor					*components.ColumnsEditorComponent
+	boardPicker						*components.BoardPickerComponent
 }
 
 // NewApp creates a new instance of the App with initial configurations.
-func NewApp(userConfig *domain.UserConfig, boardName string) (*App, error) {
+func NewApp(userConfig *domain.UserConfig, boardId string) (*App, error) {
 	if err := termui.Init(); err != nil {
 		return nil, err
 	}
 
 	width, height := termui.TerminalDimensions()
 	
-	boardOpt := userConfig.GetBoard(boardName)
+	boardOpt := userConfig.GetBoardById(boardId)
 	if boardOpt.IsNone() {
 		return nil, errors.New("Couldn't find the board while trying to add a task")
 	}
 
 	board := boardOpt.Unwrap()
 
+	// Recurring tasks that were done, or whose next occurrence is due, come back.
+	recurred, err := userConfig.RecurDueTasks(board)
+	if err != nil {
+		utils.SaveLog(utils.Error, "Failed to add the next occurrences of recurring tasks. " + err.Error(), map[string]any{"board": board.Name})
+	} else if recurred > 0 {
+		utils.SaveLog(utils.Info, "Added the next occurrences of recurring tasks", map[string]any{"board": board.Name, "count": recurred})
+	}
+
 	theme := os.Getenv(vars.ThemeFlag)
 	if theme == "" {
 		theme = "dark"
@@ -63,17 +86,23 @@ func NewApp(userConfig *domain.UserConfig, boardName string) (*App, error) {
 	app := new(App)
 	
 	app.userConfig = userConfig
-	app.boardName = boardName
+	app.boardId = boardId
+	app.board = board
 	app.window = types.Window {
 		Width: width,
 		Height: height,
 	}
 	app.theme = theme
-	app.createTaskPopup = components.NewCreateTaskPopupComponent(&app.window, userConfig, boardName)
+	app.createTaskPopup = components.NewCreateTaskPopupComponent(&app.window, userConfig, boardId, app.reportError)
 	app.confirmationPopup = components.NewConfirmationPopupComponent(&app.window)
-	app.tasksView = components.NewTasksViewComponent(&app.window, board, userConfig)
-	app.searchDialogPopup = components.NewSearchDialogPopupComponent(&app.window, app.tasksView.SetTextFilter)
-	app.columnsHeadersView = components.NewColumnsHeaderComponent(&app.window, board.Columns)
+	app.tasksView = components.NewTasksViewComponent(&app.window, board, userConfig, app.reportError)
+	app.searchDialogPopup = components.NewSearchDialogPopupComponent(&app.window, app.searchTasks)
+	app.columnsHeadersView = components.NewColumnsHeaderComponent(&app.window, board)
+	app.notificationPopup = components.NewNotificationPopupComponent(&app.window)
+	app.taskDetailsPopup = components.NewTaskDetailsPopupComponent(&app.window, board, userConfig, app.reportError)
+	app.removedTasksBrowser = components.NewRemovedTasksBrowserComponent(&app.window, board, userConfig, app.reportError)
+	app.columnsEditor = components.NewColumnsEditorComponent(&app.window, board, userConfig)
+	app.boardPicker = components.NewBoardPickerComponent(&app.window, board, userConfig)
 
 	return app, nil
 }
@@ -84,11 +113,29 @@ func (app *App) Run() {
 
 	app.render(false)
 
-	for event := range termui.PollEvents() {
-		app.handleEvent(event)
+	uiEvents := termui.PollEvents()
+	boardChanges := app.userConfig.WatchBoard(app.board, boardWatchInterval)
+
+	for {
+		select {
+		case event := <-uiEvents:
+			app.handleEvent(event)
+
+		case <-boardChanges:
+			app.handleEvent(termui.Event{Type: BoardChangedEvent})
+		}
 	}
 }
 
+// reportError logs an error that the user has to know about, like a change to the
+// board failing to save, and shows it in a notice.
+func (app *App) reportError(err error) {
+	utils.SaveLog(utils.Error, err.Error(), map[string]any{"board": app.board.Name})
+	
+	app.notificationPopup.SetMessage(err.Error())
+	app.notificationPopup.Show()
+}
+
 // Quit exits the application gracefully
 func (app *App) Quit() {
 	termui.Close()
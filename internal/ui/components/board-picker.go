@@ -0,0 +1,185 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/ui/types"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// BoardPickerComponent lists the other boards to move or copy a task to.
+type BoardPickerComponent struct {
+	Visible bool
+	// Result says where the task went once it was moved or copied, to be shown after
+	// the picker closes. It's empty if nothing was done.
+	Result	string
+
+	window		*types.Window
+	board		*domain.Board
+	userConfig	*domain.UserConfig
+	task		*domain.Task
+	// entries are the boards listed, in the order of the rows.
+	entries		[]*domain.BoardEntry
+	widget		*widgets.List
+	// message is the last error, shown under the boards until the next key.
+	message		string
+}
+
+func NewBoardPickerComponent(window *types.Window, board *domain.Board, userConfig *domain.UserConfig) *BoardPickerComponent {
+	ret := new(BoardPickerComponent)
+
+	ret.window = window
+	ret.board = board
+	ret.userConfig = userConfig
+	ret.widget = widgets.NewList()
+	ret.widget.Border = true
+	ret.widget.SelectedRowStyle = termui.NewStyle(termui.ColorBlack, userConfig.PrimaryColor)
+
+	return ret
+}
+
+// Refresh lists the other boards again.
+func (self *BoardPickerComponent) Refresh() {
+	self.entries = []*domain.BoardEntry{}
+	self.widget.Rows = []string{}
+
+	for _, entry := range self.userConfig.Boards {
+		if entry.Id == self.board.Id {
+			continue
+		}
+
+		self.entries = append(self.entries, entry)
+		self.widget.Rows = append(self.widget.Rows, fmt.Sprintf("%s  (%s)", entry.Name, entry.Dir))
+	}
+
+	if len(self.entries) == 0 {
+		self.widget.Rows = []string{"There are no other boards."}
+	}
+
+	if self.message != "" {
+		self.widget.Rows = append(self.widget.Rows, "", "[" + self.message + "](fg:red)")
+	}
+
+	self.widget.SelectedRow = max(min(self.widget.SelectedRow, len(self.entries) - 1), 0)
+}
+
+// RebindTask points the task to the one with the same ID on the board, like after
+// the board was reloaded, closing the picker if it's not there anymore.
+func (self *BoardPickerComponent) RebindTask() {
+	if !self.Visible || self.task == nil {
+		return
+	}
+
+	taskOpt := self.board.GetTaskById(self.task.Id)
+	if taskOpt.IsNone() {
+		self.Hide()
+		return
+	}
+
+	self.task = taskOpt.Unwrap()
+}
+
+// HandleInput handles keyboard inputs sent to this component. It returns a boolean
+// indicating if we should clear before we re-render.
+func (self *BoardPickerComponent) HandleInput(event termui.Event) bool {
+	self.message = ""
+
+	switch event.ID {
+	case "j", "<Down>", "<C-n>":
+		self.widget.SelectedRow = min(self.widget.SelectedRow + 1, max(len(self.entries) - 1, 0))
+
+	case "k", "<Up>", "<C-p>":
+		self.widget.SelectedRow = max(self.widget.SelectedRow - 1, 0)
+
+	case "<Enter>", "m":
+		self.transferTask(false)
+		return true
+
+	case "c":
+		self.transferTask(true)
+		return true
+
+	case "q", "<Escape>", "<C-c>":
+		self.Hide()
+		return true
+	}
+
+	return false
+}
+
+// transferTask moves the task to the selected board, or copies it there, closing the
+// picker once it's done.
+func (self *BoardPickerComponent) transferTask(isCopy bool) {
+	if self.widget.SelectedRow >= len(self.entries) {
+		return
+	}
+
+	entry := self.entries[self.widget.SelectedRow]
+
+	target, err := self.userConfig.LoadBoard(entry)
+	if err != nil {
+		self.showError(err)
+		return
+	}
+
+	key := self.board.GetTaskKey(self.task)
+
+	var added *domain.Task
+	if isCopy {
+		added, err = self.userConfig.CopyTaskToBoard(self.board, self.task, target)
+	} else {
+		added, err = self.userConfig.MoveTaskToBoard(self.board, self.task, target)
+	}
+	if err != nil {
+		self.showError(err)
+		return
+	}
+
+	self.Hide()
+	self.Result = fmt.Sprintf("%s %s to \"%s\" as %s.", utils.Cond(isCopy, "Copied", "Moved"), key, target.Name, target.GetTaskKey(added))
+	if !isCopy {
+		self.Result += fmt.Sprintf("\nUndoing the move here puts it back without taking it off \"%s\".", target.Name)
+	}
+}
+
+func (self *BoardPickerComponent) showError(err error) {
+	utils.SaveLog(utils.Error, "Failed to move a task to another board. " + err.Error(), map[string]any{"task": self.task.Title})
+
+	self.message = err.Error()
+	self.Refresh()
+}
+
+func (self *BoardPickerComponent) Hide() {
+	self.Visible = false
+	self.task = nil
+	self.message = ""
+}
+
+// Show opens the picker to move or copy the given task.
+func (self *BoardPickerComponent) Show(task *domain.Task) {
+	self.Visible = true
+	self.Result = ""
+	self.task = task
+	self.widget.SelectedRow = 0
+	self.Refresh()
+}
+
+func (self *BoardPickerComponent) Draw() {
+	self.widget.Title = fmt.Sprintf("Move \"%s\" to (<Enter> move, c copy, q close)", self.task.Title)
+	self.widget.BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+
+	self.widget.SetRect(
+		self.window.Width / 6,
+		self.window.Height / 6,
+
+		self.window.Width / 6 * 5,
+		self.window.Height / 6 * 5,
+	)
+
+	termui.Render(
+		self.widget,
+	)
+}
@@ -0,0 +1,318 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/okira-e/gotasks/internal/domain"
+	cw "github.com/okira-e/gotasks/internal/ui/custom-widgets"
+	"github.com/okira-e/gotasks/internal/ui/types"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// ColumnsEditorComponent lists the columns of the board and lets them be added,
+// renamed, moved around, colored, limited, given a role and deleted.
+type ColumnsEditorComponent struct {
+	Visible bool
+
+	window		*types.Window
+	board		*domain.Board
+	userConfig	*domain.UserConfig
+	widget		*widgets.List
+	// input is where the name or the WIP limit of a column is typed. It's only shown
+	// while one is being typed, which inputKind says.
+	input		*cw.TextInput
+	inputKind	columnInputKind
+	// deleting is the column being deleted while the column to move its tasks to is
+	// picked from the list.
+	deleting	*domain.Column
+	// message is the last error, shown under the columns until the next key.
+	message		string
+}
+
+// columnInputKind is what's being typed in the input of the columns editor.
+type columnInputKind string
+
+const (
+	noColumnInput		columnInputKind = ""
+	newColumnInput		columnInputKind = "New column"
+	renameColumnInput	columnInputKind = "Rename column"
+	wipLimitInput		columnInputKind = "WIP limit, 0 for none"
+)
+
+func NewColumnsEditorComponent(window *types.Window, board *domain.Board, userConfig *domain.UserConfig) *ColumnsEditorComponent {
+	ret := new(ColumnsEditorComponent)
+
+	ret.window = window
+	ret.board = board
+	ret.userConfig = userConfig
+	ret.widget = widgets.NewList()
+	ret.widget.Border = true
+	ret.widget.SelectedRowStyle = termui.NewStyle(termui.ColorBlack, userConfig.PrimaryColor)
+	ret.input = cw.NewTextInput()
+
+	return ret
+}
+
+// Refresh lists the columns again, like after the board was reloaded.
+func (self *ColumnsEditorComponent) Refresh() {
+	if self.deleting != nil && !slices.Contains(self.board.Columns, self.deleting) {
+		self.deleting = nil
+	}
+
+	self.widget.Rows = []string{}
+
+	for i, column := range self.board.Columns {
+		details := []string{string(column.Role), fmt.Sprintf("%d tasks", len(self.board.Tasks[column.Id]))}
+		if column.WipLimit > 0 {
+			details = append(details, fmt.Sprintf("WIP limit %d", column.WipLimit))
+		}
+		if column.Color != "" {
+			details = append(details, column.Color)
+		}
+
+		row := fmt.Sprintf("%d. %s  (%s)", i + 1, column.Name, strings.Join(details, ", "))
+		if column == self.deleting {
+			row += "  [deleting](fg:red)"
+		}
+
+		self.widget.Rows = append(self.widget.Rows, row)
+	}
+
+	if self.message != "" {
+		self.widget.Rows = append(self.widget.Rows, "", "[" + self.message + "](fg:red)")
+	}
+
+	self.widget.SelectedRow = max(min(self.widget.SelectedRow, len(self.board.Columns) - 1), 0)
+}
+
+// HandleInput handles keyboard inputs sent to this component. It returns a boolean
+// indicating if we should clear before we re-render.
+func (self *ColumnsEditorComponent) HandleInput(event termui.Event) bool {
+	self.message = ""
+
+	if self.inputKind != noColumnInput {
+		return self.handleInputKeys(event)
+	}
+
+	if self.deleting != nil {
+		return self.handleDeletingKeys(event)
+	}
+
+	column := self.getSelectedColumn()
+
+	switch event.ID {
+	case "j", "<Down>", "<C-n>":
+		self.widget.SelectedRow = min(self.widget.SelectedRow + 1, len(self.board.Columns) - 1)
+
+	case "k", "<Up>", "<C-p>":
+		self.widget.SelectedRow = max(self.widget.SelectedRow - 1, 0)
+
+	case "a":
+		self.inputKind = newColumnInput
+		self.input.Flush()
+
+	case "r":
+		if column != nil {
+			self.inputKind = renameColumnInput
+			self.input.SetText(column.Name)
+		}
+
+	case "w":
+		if column != nil {
+			self.inputKind = wipLimitInput
+			self.input.SetText(strconv.Itoa(column.WipLimit))
+		}
+
+	case "J", "K":
+		if column != nil {
+			position := self.widget.SelectedRow + utils.Cond(event.ID == "J", 1, -1)
+			self.showError(self.userConfig.MoveColumn(self.board.Id, column.Id, position))
+			self.widget.SelectedRow = self.board.GetColumnIndex(column.Id)
+		}
+
+	case "c":
+		if column != nil {
+			// Cycles through the colors, and back to none after the last one.
+			i := slices.Index(domain.ColumnColors, column.Color)
+			color := ""
+			if i + 1 < len(domain.ColumnColors) {
+				color = domain.ColumnColors[i + 1]
+			}
+			self.showError(self.userConfig.SetColumnColor(self.board.Id, column.Id, color))
+		}
+
+	case "R":
+		if column != nil {
+			// Cycles through the roles, and back to the first one after the last one.
+			i := slices.Index(domain.ColumnRoles, column.Role)
+			role := domain.ColumnRoles[(i + 1) % len(domain.ColumnRoles)]
+			self.showError(self.userConfig.SetColumnRole(self.board.Id, column.Id, role))
+		}
+
+	case "d":
+		if column == nil {
+			break
+		}
+
+		if len(self.board.Tasks[column.Id]) == 0 {
+			self.showError(self.userConfig.DeleteColumn(self.board.Id, column.Id, ""))
+			break
+		}
+
+		// The tasks have to go somewhere, so the column to move them to is picked first.
+		self.deleting = column
+		self.widget.SelectedRow = utils.Cond(self.widget.SelectedRow == 0, 1, self.widget.SelectedRow - 1)
+
+	case "q", "<Escape>", "<C-c>":
+		self.Hide()
+		return true
+	}
+
+	self.Refresh()
+
+	return true
+}
+
+// handleDeletingKeys picks the column to move the tasks of the column being deleted to.
+func (self *ColumnsEditorComponent) handleDeletingKeys(event termui.Event) bool {
+	switch event.ID {
+	case "j", "<Down>", "<C-n>":
+		self.widget.SelectedRow = min(self.widget.SelectedRow + 1, len(self.board.Columns) - 1)
+
+	case "k", "<Up>", "<C-p>":
+		self.widget.SelectedRow = max(self.widget.SelectedRow - 1, 0)
+
+	case "<Enter>":
+		moveTo := self.getSelectedColumn()
+		if moveTo == nil {
+			break
+		}
+
+		self.showError(self.userConfig.DeleteColumn(self.board.Id, self.deleting.Id, moveTo.Id))
+		self.deleting = nil
+		self.widget.SelectedRow = self.board.GetColumnIndex(moveTo.Id)
+
+	case "q", "<Escape>", "<C-c>":
+		self.deleting = nil
+	}
+
+	self.Refresh()
+
+	return true
+}
+
+// handleInputKeys sends the keys to the input of a column's name or WIP limit,
+// saving it on <Enter>.
+func (self *ColumnsEditorComponent) handleInputKeys(event termui.Event) bool {
+	switch event.ID {
+	case "<Escape>", "<C-c>":
+		self.inputKind = noColumnInput
+		return true
+
+	case "<Enter>":
+		inputKind := self.inputKind
+		self.inputKind = noColumnInput
+		text := strings.TrimSpace(self.input.GetText())
+		column := self.getSelectedColumn()
+
+		switch inputKind {
+		case newColumnInput:
+			// New columns go right after the one selected.
+			added, err := self.userConfig.AddColumn(self.board.Id, text, domain.ActiveRole, self.widget.SelectedRow + 1)
+			self.showError(err)
+			if err == nil {
+				self.widget.SelectedRow = self.board.GetColumnIndex(added.Id)
+			}
+
+		case renameColumnInput:
+			if column != nil {
+				self.showError(self.userConfig.RenameColumn(self.board.Id, column.Id, text))
+			}
+
+		case wipLimitInput:
+			limit, err := strconv.Atoi(text)
+			if err != nil {
+				self.message = fmt.Sprintf("The WIP limit \"%s\" should be a number", text)
+			} else if column != nil {
+				self.showError(self.userConfig.SetWipLimit(self.board.Id, column.Id, limit))
+			}
+		}
+
+		self.Refresh()
+		return true
+
+	default:
+		self.input.HandleInput(event.ID, true)
+	}
+
+	return false
+}
+
+// showError shows the error under the columns, if there's one.
+func (self *ColumnsEditorComponent) showError(err error) {
+	if err != nil {
+		utils.SaveLog(utils.Warn, err.Error(), nil)
+		self.message = err.Error()
+	}
+}
+
+func (self *ColumnsEditorComponent) getSelectedColumn() *domain.Column {
+	if self.widget.SelectedRow < 0 || self.widget.SelectedRow >= len(self.board.Columns) {
+		return nil
+	}
+
+	return self.board.Columns[self.widget.SelectedRow]
+}
+
+func (self *ColumnsEditorComponent) Hide() {
+	self.Visible = false
+	self.inputKind = noColumnInput
+	self.deleting = nil
+	self.message = ""
+}
+
+// Show opens the editor on the first column.
+func (self *ColumnsEditorComponent) Show() {
+	self.Visible = true
+	self.widget.SelectedRow = 0
+	self.Refresh()
+}
+
+func (self *ColumnsEditorComponent) Draw() {
+	if self.deleting != nil {
+		self.widget.Title = fmt.Sprintf(
+			"Move the %d tasks of \"%s\" to (<Enter> delete, <Escape> cancel)",
+			len(self.board.Tasks[self.deleting.Id]),
+			self.deleting.Name,
+		)
+	} else {
+		self.widget.Title = "Columns (a add, r rename, J/K move, w WIP limit, c color, R role, d delete, q close)"
+	}
+	self.widget.BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+
+	x1 := self.window.Width / 6
+	y1 := self.window.Height / 6
+	x2 := self.window.Width / 6 * 5
+	y2 := self.window.Height / 6 * 5
+
+	self.widget.SetRect(x1, y1, x2, y2)
+
+	termui.Render(
+		self.widget,
+	)
+
+	if self.inputKind != noColumnInput {
+		self.input.GetDrawableWidget().Title = fmt.Sprintf("%s (<Enter> save, <Escape> cancel)", self.inputKind)
+		self.input.GetDrawableWidget().BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+		self.input.GetDrawableWidget().SetRect(x1 + 1, y2 - 4, x2 - 1, y2 - 1)
+
+		termui.Render(
+			self.input.GetDrawableWidget(),
+		)
+	}
+}
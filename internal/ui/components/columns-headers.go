@@ -1,23 +1,26 @@
 package components
 
 import (
+	"fmt"
+
 	"github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
+	"github.com/okira-e/gotasks/internal/domain"
 	"github.com/okira-e/gotasks/internal/ui/types"
 	"github.com/okira-e/gotasks/internal/utils"
 )
 
 type ColumnsHeaderComponent struct {
     columnBoxes []*widgets.Paragraph
-    columnNames	[]string
+    board		*domain.Board
     window		*types.Window
 }
 
-func NewColumnsHeaderComponent(window *types.Window, columnNames []string) *ColumnsHeaderComponent {
+func NewColumnsHeaderComponent(window *types.Window, board *domain.Board) *ColumnsHeaderComponent {
 	component := new(ColumnsHeaderComponent)
 	
 	component.window = window
-	component.columnNames = columnNames
+	component.board = board
 	
 	return component
 }
@@ -33,10 +36,18 @@ func (self *ColumnsHeaderComponent) GetAllDrawableWidgets() []termui.Drawable {
 }
 
 
+// Draw renders a header for every column, in the color of the column if it has one.
+// Columns with a WIP limit show how many tasks they have against it, like "In Progress 4/3",
+// in red once it's exceeded.
 func (self *ColumnsHeaderComponent) Draw() {
-	widgetWidth := self.window.Width / len(self.columnNames)
+	if len(self.board.Columns) == 0 {
+		return
+	}
+	
+	widgetWidth := self.window.Width / len(self.board.Columns)
+	self.columnBoxes = []*widgets.Paragraph{}
 
-	for i, columnName := range self.columnNames {
+	for i, column := range self.board.Columns {
 		widget := widgets.NewParagraph()
 		widget.Border = true
 
@@ -47,7 +58,23 @@ func (self *ColumnsHeaderComponent) Draw() {
 
 		widget.SetRect(x1, y1, x2, y2)
 
-		widget.Text = utils.CenterText(columnName, widgetWidth, true)
+		header := column.Name
+		if column.WipLimit > 0 {
+			header += fmt.Sprintf(" %d/%d", len(self.board.Tasks[column.Id]), column.WipLimit)
+		}
+		
+		if column.Color != "" {
+			color := column.GetColor(termui.ColorClear)
+			widget.TextStyle = termui.NewStyle(color, termui.ColorClear, termui.ModifierBold)
+			widget.BorderStyle = termui.NewStyle(color)
+		}
+		
+		if self.board.IsOverWipLimit(column) {
+			widget.TextStyle = termui.NewStyle(termui.ColorRed, termui.ColorClear, termui.ModifierBold)
+			widget.BorderStyle = termui.NewStyle(termui.ColorRed)
+		}
+
+		widget.Text = utils.CenterText(header, widgetWidth, true)
 		
 		widget.WrapText = true
 
@@ -1,7 +1,12 @@
 package components
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
 	"github.com/okira-e/gotasks/internal/domain"
 	cw "github.com/okira-e/gotasks/internal/ui/custom-widgets"
 	"github.com/okira-e/gotasks/internal/ui/types"
@@ -12,25 +17,61 @@ type CreateTaskPopup struct {
 	Visible 		bool
 	// EditingTask if this is set, the widget becomes an edit popup that shows & edits existing data.
 	EditingTask		*domain.Task
+	// editingTaskGone is set if the task being edited was deleted by another process.
+	// Saving is refused then, it would only bring the task back as a new one.
+	editingTaskGone	bool
 	
 	window			*types.Window
 	titleInput   	*cw.TextInput
 	descInput    	*cw.TextInput
+	priorityInput	*cw.TextInput
+	dueDateInput	*cw.TextInput
+	estimateInput	*cw.TextInput
+	assigneeInput	*cw.TextInput
+	labelsInput		*cw.TextInput
+	epicInput		*cw.TextInput
+	blockedByInput	*cw.TextInput
+	repeatsInput	*cw.TextInput
 	focusedField 	*cw.TextInput
+	// invalidField is the field that failed to be read on the last save, if any.
+	invalidField	*cw.TextInput
+	// templatePicker lists the templates a new task can start from. It's shown over the
+	// description while one is being picked.
+	templatePicker	*widgets.List
+	templates		[]*domain.TaskTemplate
+	pickingTemplate	bool
+	// template is the last template picked for the new task, and checklist is what the
+	// templates picked for it put on its checklist.
+	template		*domain.TaskTemplate
+	checklist		[]*domain.ChecklistItem
 	userConfig		*domain.UserConfig
-	boardName		string
+	boardId			string
+	// reportError shows the user that saving the task failed.
+	reportError		func(err error)
 }
 
 // NewCreateTaskPopupComponent initializes a new popup.
-func NewCreateTaskPopupComponent(window *types.Window, config *domain.UserConfig, boardName string) *CreateTaskPopup {
+func NewCreateTaskPopupComponent(window *types.Window, config *domain.UserConfig, boardId string, reportError func(err error)) *CreateTaskPopup {
 	component := new(CreateTaskPopup)
 	
 	component.Visible = false
 	component.window = window
 	component.userConfig = config
-	component.boardName = boardName
+	component.boardId = boardId
+	component.reportError = reportError
 	component.titleInput = cw.NewTextInput()
 	component.descInput = cw.NewTextInput()
+	component.priorityInput = cw.NewTextInput()
+	component.dueDateInput = cw.NewTextInput()
+	component.estimateInput = cw.NewTextInput()
+	component.assigneeInput = cw.NewTextInput()
+	component.labelsInput = cw.NewTextInput()
+	component.epicInput = cw.NewTextInput()
+	component.blockedByInput = cw.NewTextInput()
+	component.repeatsInput = cw.NewTextInput()
+	component.templatePicker = widgets.NewList()
+	component.templatePicker.Border = true
+	component.templatePicker.SelectedRowStyle = termui.NewStyle(termui.ColorBlack, config.PrimaryColor)
 
 	component.focusedField = component.titleInput
 	
@@ -42,18 +83,133 @@ func (self *CreateTaskPopup) SetEditEditingTask(task *domain.Task) {
 	
 	self.titleInput.SetText(task.Title)
 	self.descInput.SetText(task.Description)
+	self.priorityInput.SetText(string(task.Priority))
+	self.dueDateInput.SetText(task.DueDate)
+	self.estimateInput.SetText(task.Estimate.String())
+	self.assigneeInput.SetText(task.Assignee)
+	self.labelsInput.SetText(strings.Join(task.Labels, ", "))
+	self.epicInput.SetText(task.Epic)
+	
+	blockerKeys := []string{}
+	boardOpt := self.userConfig.GetBoardById(self.boardId)
+	if boardOpt.IsSome() {
+		board := boardOpt.Unwrap()
+		for _, blocker := range board.GetBlockers(task) {
+			blockerKeys = append(blockerKeys, board.GetTaskKey(blocker))
+		}
+	}
+	self.blockedByInput.SetText(strings.Join(blockerKeys, ", "))
+	self.repeatsInput.SetText(task.Recurrence.String())
+}
+
+// getFields returns every input field in the order <Tab> goes through them.
+func (self *CreateTaskPopup) getFields() []*cw.TextInput {
+	return []*cw.TextInput{
+		self.titleInput,
+		self.priorityInput,
+		self.dueDateInput,
+		self.estimateInput,
+		self.assigneeInput,
+		self.labelsInput,
+		self.epicInput,
+		self.blockedByInput,
+		self.repeatsInput,
+		self.descInput,
+	}
+}
+
+// readFields reads what was typed in the fields other than the title and the
+// description. It returns a function that sets them on a task, or the field that
+// couldn't be read along with the error.
+func (self *CreateTaskPopup) readFields(board *domain.Board) (func(task *domain.Task), *cw.TextInput, error) {
+	priority, err := domain.ParsePriority(self.priorityInput.GetText())
+	if err != nil {
+		return nil, self.priorityInput, err
+	}
+	
+	dueDate, err := domain.ParseDueDate(self.dueDateInput.GetText())
+	if err != nil {
+		return nil, self.dueDateInput, err
+	}
+	
+	estimate, err := domain.ParseEstimate(self.estimateInput.GetText())
+	if err != nil {
+		return nil, self.estimateInput, err
+	}
+	
+	assignee := strings.TrimSpace(self.assigneeInput.GetText())
+	labels := domain.ParseLabels(self.labelsInput.GetText())
+	epic := strings.TrimSpace(self.epicInput.GetText())
+	
+	taskId := ""
+	if self.EditingTask != nil {
+		taskId = self.EditingTask.Id
+	}
+	blockedBy, err := board.ParseBlockers(taskId, self.blockedByInput.GetText())
+	if err != nil {
+		return nil, self.blockedByInput, err
+	}
+	
+	recurrence, err := domain.ParseRecurrence(self.repeatsInput.GetText())
+	if err != nil {
+		return nil, self.repeatsInput, err
+	}
+	
+	return func(task *domain.Task) {
+		task.Priority = priority
+		task.DueDate = dueDate
+		task.Estimate = estimate
+		task.Assignee = assignee
+		task.Labels = labels
+		task.Epic = epic
+		task.Recurrence = recurrence
+		// Blockers that were taken off the board, like into the trash, can't be typed
+		// in, so they're kept as they are.
+		for _, blockerId := range task.BlockedBy {
+			blockerOpt := board.GetTaskById(blockerId)
+			if blockerOpt.IsNone() {
+				blockedBy = append(blockedBy, blockerId)
+			}
+		}
+		task.BlockedBy = blockedBy
+	}, nil, nil
+}
+
+// RebindEditingTask points the task being edited to the one with the same ID on
+// the given board, like after the board was reloaded. It returns false if the task
+// isn't there anymore, in which case it can't be saved.
+func (self *CreateTaskPopup) RebindEditingTask(board *domain.Board) bool {
+	if self.EditingTask == nil {
+		return true
+	}
+	
+	taskOpt := board.GetTaskById(self.EditingTask.Id)
+	if taskOpt.IsNone() {
+		self.editingTaskGone = true
+		return false
+	}
+	
+	self.EditingTask = taskOpt.Unwrap()
+	return true
 }
 
 func (self *CreateTaskPopup) GetAllDrawableWidgets() []termui.Drawable {
-	return []termui.Drawable{
-		self.titleInput.GetDrawableWidget(),
-		self.descInput.GetDrawableWidget(),
+	ret := []termui.Drawable{}
+	
+	for _, field := range self.getFields() {
+		ret = append(ret, field.GetDrawableWidget())
 	}
+	
+	return ret
 }
 
 // HandleKeyboardEvent handles every event for this widget. It returns a flag
 // indicating if the next render should clear the view.
 func (self *CreateTaskPopup) HandleKeyboardEvent(event termui.Event) bool {
+	if self.pickingTemplate {
+		return self.handleTemplatePickerKeys(event)
+	}
+	
 	if event.ID ==  "<C-c>" {
 		self.Hide()
 		return true
@@ -61,13 +217,21 @@ func (self *CreateTaskPopup) HandleKeyboardEvent(event termui.Event) bool {
 	} else if event.ID == "<Tab>" {
 		self.ToggleFocusOnNextField()
 		
+	} else if event.ID == "<C-t>" {
+		self.showTemplatePicker()
+		
 	} else if event.ID == "<Enter>" {
 		if self.titleInput.GetText() == "" {
 			return false
 		}
 		
+		if self.editingTaskGone {
+			self.reportError(errors.New("The task was deleted outside of gotasks, so it can't be saved.\nPress <C-c> to close it."))
+			return true
+		}
+		
 		// Save the task.
-		boardOpt := self.userConfig.GetBoard(self.boardName)
+		boardOpt := self.userConfig.GetBoardById(self.boardId)
 		board := boardOpt.Expect("Board was found to be null while handling <Enter> on task creation.")
 		
 		if len(board.Columns) == 0 {
@@ -80,35 +244,49 @@ func (self *CreateTaskPopup) HandleKeyboardEvent(event termui.Event) bool {
 			return false
 		}
 		
-		// If we are not in edit mode, create a new task. Otherwise, just simple edit the pointer
-		// to the task we're editing.
+		setFields, invalidField, err := self.readFields(board)
+		if err != nil {
+			utils.SaveLog(utils.Info, err.Error(), nil)
+			
+			self.invalidField = invalidField
+			self.focusedField = invalidField
+			return false
+		}
+		
+		// If we are not in edit mode, create a new task. Otherwise, edit the task we're editing.
 		
 		if self.EditingTask == nil {
 			task := domain.NewTask(
 				self.titleInput.GetText(), 
 				self.descInput.GetText(),
 			)
+			setFields(task)
+			task.Checklist = self.checklist
 			
-			err := self.userConfig.AddTask(self.boardName, task)
+			err := self.userConfig.AddTask(self.boardId, task)
 			if err != nil {
-				utils.SaveLog(utils.Error, err.Error(), map[string]any{"boardName": self.boardName, "task": task})
+				self.reportError(fmt.Errorf("Failed to add the task. %s", err))
 			}
 		} else {
-			self.EditingTask.Title = self.titleInput.GetText()
-			self.EditingTask.Description = self.descInput.GetText()
+			title := self.titleInput.GetText()
+			description := self.descInput.GetText()
 			
-			self.userConfig.UpdateBoard(board)
+			err := self.userConfig.EditTask(self.boardId, self.EditingTask, func(task *domain.Task) {
+				task.Title = title
+				task.Description = description
+				setFields(task)
+			})
+			if err != nil {
+				self.reportError(fmt.Errorf("Failed to save the task. %s", err))
+			}
 		}
 		
 		self.Hide()
 		return true
 		
 	} else {
-		// Disable new lines in the title.
-		disableNewLines := false
-		if self.focusedField == self.titleInput {
-			disableNewLines = true
-		}
+		// Only the description can have new lines.
+		disableNewLines := self.focusedField != self.descInput
 
 		self.focusedField.HandleInput(event.ID, disableNewLines)
 	}
@@ -116,6 +294,88 @@ func (self *CreateTaskPopup) HandleKeyboardEvent(event termui.Event) bool {
 	return false
 }
 
+// showTemplatePicker lists the templates of the board to pick one for the new task
+// from. Tasks being edited can't start from a template.
+func (self *CreateTaskPopup) showTemplatePicker() {
+	if self.EditingTask != nil {
+		return
+	}
+	
+	self.templates = self.getTemplates()
+	if len(self.templates) == 0 {
+		return
+	}
+	
+	self.templatePicker.Rows = []string{}
+	for _, template := range self.templates {
+		details := []string{}
+		if template.Title != "" {
+			details = append(details, "\"" + template.Title + "\"")
+		}
+		for _, label := range template.Labels {
+			details = append(details, "#" + label)
+		}
+		if len(template.Checklist) > 0 {
+			details = append(details, fmt.Sprintf("%d checklist %s", len(template.Checklist), utils.Cond(len(template.Checklist) == 1, "item", "items")))
+		}
+		
+		self.templatePicker.Rows = append(self.templatePicker.Rows, template.Name + "  " + strings.Join(details, "  "))
+	}
+	
+	self.templatePicker.SelectedRow = 0
+	self.pickingTemplate = true
+}
+
+// handleTemplatePickerKeys handles the keys while a template is being picked. It
+// returns a flag indicating if the next render should clear the view.
+func (self *CreateTaskPopup) handleTemplatePickerKeys(event termui.Event) bool {
+	switch event.ID {
+	case "j", "<Down>", "<C-n>":
+		self.templatePicker.SelectedRow = min(self.templatePicker.SelectedRow + 1, len(self.templates) - 1)
+		
+	case "k", "<Up>", "<C-p>":
+		self.templatePicker.SelectedRow = max(self.templatePicker.SelectedRow - 1, 0)
+		
+	case "<Enter>":
+		self.applyTemplate(self.templates[self.templatePicker.SelectedRow])
+		self.pickingTemplate = false
+		return true
+		
+	case "<Escape>", "<C-c>":
+		self.pickingTemplate = false
+		return true
+	}
+	
+	return false
+}
+
+// applyTemplate fills the fields of the new task going by the template, keeping what
+// was already typed in them.
+func (self *CreateTaskPopup) applyTemplate(template *domain.TaskTemplate) {
+	task := domain.NewTask(self.titleInput.GetText(), self.descInput.GetText())
+	task.Labels = domain.ParseLabels(self.labelsInput.GetText())
+	task.Checklist = self.checklist
+	
+	template.ApplyTo(task)
+	
+	self.titleInput.SetText(task.Title)
+	self.descInput.SetText(task.Description)
+	self.labelsInput.SetText(strings.Join(task.Labels, ", "))
+	self.checklist = task.Checklist
+	self.template = template
+	self.focusedField = self.titleInput
+}
+
+// getTemplates returns the templates the tasks of the board can start from.
+func (self *CreateTaskPopup) getTemplates() []*domain.TaskTemplate {
+	boardOpt := self.userConfig.GetBoardById(self.boardId)
+	if boardOpt.IsNone() {
+		return []*domain.TaskTemplate{}
+	}
+	
+	return self.userConfig.GetTemplates(boardOpt.Unwrap())
+}
+
 func (self *CreateTaskPopup) Show() {
 	self.Visible = true
 }
@@ -127,58 +387,106 @@ func (self *CreateTaskPopup) Hide() {
 
 // Toggles the focus onto the next input field.
 func (self *CreateTaskPopup) ToggleFocusOnNextField() {
-	if self.focusedField != nil {
-		self.focusedField = utils.Cond(
-			self.focusedField == self.titleInput,
-			self.descInput,
-			self.titleInput,
-		)
-	} else { // Shouldn't happen
-		self.focusedField = self.titleInput
+	fields := self.getFields()
+	
+	for i, field := range fields {
+		if field == self.focusedField {
+			self.focusedField = fields[(i + 1) % len(fields)]
+			return
+		}
 	}
+	
+	// Shouldn't happen
+	self.focusedField = self.titleInput
 }
 
 // Draw renders the popup if visible.
 func (self *CreateTaskPopup) Draw() {
 	self.Visible = true
 	
+	x1 := self.window.Width/4
+	x2 := self.window.Width/4*3
 	y1 := self.window.Height/4
+	thirdWidth := (x2 - x1)/3
 
 	self.titleInput.GetDrawableWidget().Title = "Title"
+	if self.template != nil {
+		self.titleInput.GetDrawableWidget().Title = fmt.Sprintf("Title (from the \"%s\" template)", self.template.Name)
+	} else if self.EditingTask == nil && len(self.getTemplates()) > 0 {
+		self.titleInput.GetDrawableWidget().Title = "Title (<C-t> start from a template)"
+	}
+	self.titleInput.GetDrawableWidget().SetRect(x1, y1, x2, y1+3)
 	
-	self.titleInput.GetDrawableWidget().SetRect(
-		self.window.Width/4,
-		self.window.Height/4,
-
-		self.window.Width/4*3,
-		y1+3,
-	)
+	// The rest of the fields go in three rows of small inputs under the title, with the
+	// blockers taking two thirds of the last one.
+	self.priorityInput.GetDrawableWidget().Title = "Priority (l/m/h/u)"
+	self.priorityInput.GetDrawableWidget().SetRect(x1, y1+3, x1+thirdWidth, y1+6)
+	
+	self.dueDateInput.GetDrawableWidget().Title = "Due date (YYYY-MM-DD)"
+	self.dueDateInput.GetDrawableWidget().SetRect(x1+thirdWidth, y1+3, x1+thirdWidth*2, y1+6)
+	
+	self.estimateInput.GetDrawableWidget().Title = "Estimate (3p, 4h)"
+	self.estimateInput.GetDrawableWidget().SetRect(x1+thirdWidth*2, y1+3, x2, y1+6)
+	
+	self.assigneeInput.GetDrawableWidget().Title = "Assignee"
+	self.assigneeInput.GetDrawableWidget().SetRect(x1, y1+6, x1+thirdWidth, y1+9)
+	
+	self.labelsInput.GetDrawableWidget().Title = "Labels (comma separated)"
+	self.labelsInput.GetDrawableWidget().SetRect(x1+thirdWidth, y1+6, x1+thirdWidth*2, y1+9)
+	
+	self.epicInput.GetDrawableWidget().Title = "Epic"
+	self.epicInput.GetDrawableWidget().SetRect(x1+thirdWidth*2, y1+6, x2, y1+9)
+	
+	self.blockedByInput.GetDrawableWidget().Title = "Blocked by (task keys)"
+	self.blockedByInput.GetDrawableWidget().SetRect(x1, y1+9, x1+thirdWidth*2, y1+12)
+	
+	self.repeatsInput.GetDrawableWidget().Title = "Repeats (weekly, 3d)"
+	self.repeatsInput.GetDrawableWidget().SetRect(x1+thirdWidth*2, y1+9, x2, y1+12)
 
 	self.descInput.GetDrawableWidget().Title = "Description"
 	self.descInput.GetDrawableWidget().SetRect(
-		self.window.Width/4,
-		self.titleInput.GetDrawableWidget().Max.Y, // 3 is the height of the Title widget above it.
-		self.window.Width/4*3,
+		x1,
+		y1+12, // 12 is the height of the fields above it.
+		x2,
 		self.window.Height/4*3,
 	)
 	
-	// Set the border to be the primary color on the input field that is in focus.
-	self.focusedField.GetDrawableWidget().BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
-	// Remove the primary color from the non-focused
-	if self.focusedField == self.titleInput {
-		self.descInput.GetDrawableWidget().BorderStyle = termui.NewStyle(termui.ColorClear)
-	} else {
-		self.titleInput.GetDrawableWidget().BorderStyle = termui.NewStyle(termui.ColorClear)
+	// Set the border to be the primary color on the input field that is in focus, and
+	// red on the one that couldn't be read.
+	for _, field := range self.getFields() {
+		style := termui.NewStyle(termui.ColorClear)
+		if field == self.focusedField {
+			style = termui.NewStyle(self.userConfig.PrimaryColor)
+		}
+		if field == self.invalidField {
+			style = termui.NewStyle(termui.ColorRed)
+		}
+		
+		field.GetDrawableWidget().BorderStyle = style
 	}
 	
 	termui.Render(
 		self.GetAllDrawableWidgets()...
 	)
+	
+	if self.pickingTemplate {
+		self.templatePicker.Title = "Templates (<Enter> pick, <Escape> cancel)"
+		self.templatePicker.BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+		self.templatePicker.SetRect(x1, y1+12, x2, self.window.Height/4*3)
+		
+		termui.Render(self.templatePicker)
+	}
 }
 
 func (self *CreateTaskPopup) reset() {
 	self.focusedField = self.titleInput
+	self.invalidField = nil
 	self.EditingTask = nil
-	self.titleInput.Flush()
-	self.descInput.Flush()
+	self.editingTaskGone = false
+	self.pickingTemplate = false
+	self.template = nil
+	self.checklist = nil
+	for _, field := range self.getFields() {
+		field.Flush()
+	}
 }
\ No newline at end of file
@@ -0,0 +1,77 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/okira-e/gotasks/internal/ui/types"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// NotificationComponent shows a message on top of the board until any key is pressed.
+type NotificationComponent struct {
+	Visible bool
+
+	window	*types.Window
+	widget 	*widgets.Paragraph
+}
+
+func NewNotificationPopupComponent(window *types.Window) *NotificationComponent {
+	ret := new(NotificationComponent)
+
+	ret.widget = widgets.NewParagraph()
+	ret.widget.Title = "Notice"
+	ret.widget.Border = true
+	ret.window = window
+
+	return ret
+}
+
+// SetMessage sets the message to show. Every line of it is centered on its own.
+func (self *NotificationComponent) SetMessage(message string) {
+	lines := strings.Split(message, "\n")
+
+	longestLine := 0
+	for _, line := range lines {
+		longestLine = max(longestLine, len(line))
+	}
+
+	widgetHeight := len(lines) + 3
+	widgetWidth := longestLine + 4
+
+	self.widget.SetRect(
+		self.window.Width / 2 - widgetWidth / 2,
+		self.window.Height / 2 - widgetHeight / 2,
+
+		self.window.Width / 2 + widgetWidth / 2 + widgetWidth % 2,
+		self.window.Height / 2 + widgetHeight / 2 + widgetHeight % 2,
+	)
+
+	self.widget.Text = ""
+	for _, line := range lines {
+		self.widget.Text += utils.CenterText(line, widgetWidth, true) + "\n"
+	}
+	self.widget.Text += utils.CenterText("Press any key", widgetWidth, true)
+}
+
+// HandleInput handles keyboard inputs sent to this component. It returns a boolean
+// indicating if we should clear before we re-render.
+func (self *NotificationComponent) HandleInput(event termui.Event) bool {
+	self.Hide()
+	return true
+}
+
+func (self *NotificationComponent) Hide() {
+	self.Visible = false
+}
+
+func (self *NotificationComponent) Show() {
+	self.Visible = true
+}
+
+func (self *NotificationComponent) Draw() {
+	termui.Render(
+		self.widget,
+	)
+}
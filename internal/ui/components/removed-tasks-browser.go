@@ -0,0 +1,139 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/ui/types"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// RemovedTasksBrowserComponent lists the tasks in the trash, or in the archive, of
+// the board and lets them be restored.
+type RemovedTasksBrowserComponent struct {
+	Visible bool
+
+	window		*types.Window
+	board		*domain.Board
+	userConfig	*domain.UserConfig
+	// shelf is either the trash or the archive, whichever is being browsed.
+	shelf		domain.TaskShelf
+	// tasks are the tasks listed, in the order of the rows.
+	tasks		[]*domain.RemovedTask
+	widget		*widgets.List
+	// reportError shows the user that restoring a task failed.
+	reportError	func(err error)
+}
+
+func NewRemovedTasksBrowserComponent(window *types.Window, board *domain.Board, userConfig *domain.UserConfig, reportError func(err error)) *RemovedTasksBrowserComponent {
+	ret := new(RemovedTasksBrowserComponent)
+
+	ret.window = window
+	ret.board = board
+	ret.userConfig = userConfig
+	ret.reportError = reportError
+	ret.shelf = domain.TrashShelf
+	ret.widget = widgets.NewList()
+	ret.widget.Border = true
+	ret.widget.SelectedRowStyle = termui.NewStyle(termui.ColorBlack, userConfig.PrimaryColor)
+
+	return ret
+}
+
+// Refresh lists the tasks on the shelf again, like after the board was reloaded.
+func (self *RemovedTasksBrowserComponent) Refresh() {
+	shelf := self.board.GetShelf(self.shelf)
+
+	self.tasks = []*domain.RemovedTask{}
+	self.widget.Rows = []string{}
+
+	// The last one removed goes on top.
+	for i := len(shelf) - 1; i >= 0; i-- {
+		self.tasks = append(self.tasks, shelf[i])
+		self.widget.Rows = append(self.widget.Rows, fmt.Sprintf(
+			"%s  %s  (from %s, %s)",
+			self.board.GetTaskKey(shelf[i].Task),
+			shelf[i].Task.Title,
+			self.board.GetColumnName(shelf[i].Column),
+			utils.FormatTime(shelf[i].RemovedAt),
+		))
+	}
+
+	if len(self.widget.Rows) == 0 {
+		self.widget.Rows = []string{fmt.Sprintf("The %s is empty.", self.shelf)}
+	}
+
+	self.widget.SelectedRow = min(self.widget.SelectedRow, len(self.widget.Rows) - 1)
+}
+
+// HandleInput handles keyboard inputs sent to this component. It returns a boolean
+// indicating if we should clear before we re-render.
+func (self *RemovedTasksBrowserComponent) HandleInput(event termui.Event) bool {
+	switch event.ID {
+	case "j", "<Down>", "<C-n>":
+		self.widget.ScrollDown()
+
+	case "k", "<Up>", "<C-p>":
+		self.widget.ScrollUp()
+
+	case "<Tab>":
+		self.shelf = utils.Cond(self.shelf == domain.TrashShelf, domain.ArchiveShelf, domain.TrashShelf)
+		self.widget.SelectedRow = 0
+		self.Refresh()
+		return true
+
+	case "r":
+		if self.widget.SelectedRow < len(self.tasks) {
+			task := self.tasks[self.widget.SelectedRow]
+
+			err := self.userConfig.RestoreTask(self.board.Id, task.Task.Id)
+			if err != nil {
+				self.reportError(err)
+			}
+
+			self.Refresh()
+			return true
+		}
+
+	case "q", "<Escape>", "<C-c>":
+		self.Hide()
+		return true
+	}
+
+	return false
+}
+
+func (self *RemovedTasksBrowserComponent) Hide() {
+	self.Visible = false
+}
+
+// Show opens the browser on the trash.
+func (self *RemovedTasksBrowserComponent) Show() {
+	self.Visible = true
+	self.shelf = domain.TrashShelf
+	self.widget.SelectedRow = 0
+	self.Refresh()
+}
+
+func (self *RemovedTasksBrowserComponent) Draw() {
+	self.widget.Title = utils.Cond(
+		self.shelf == domain.TrashShelf,
+		"Trash (<Tab> archive, r restore, q close)",
+		"Archive (<Tab> trash, r restore, q close)",
+	)
+	self.widget.BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+
+	self.widget.SetRect(
+		self.window.Width / 6,
+		self.window.Height / 6,
+
+		self.window.Width / 6 * 5,
+		self.window.Height / 6 * 5,
+	)
+
+	termui.Render(
+		self.widget,
+	)
+}
@@ -0,0 +1,322 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/okira-e/gotasks/internal/domain"
+	cw "github.com/okira-e/gotasks/internal/ui/custom-widgets"
+	"github.com/okira-e/gotasks/internal/ui/types"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// TaskDetailsComponent shows everything about a task, along with its history. It's
+// also where the checklist of the task is checked off and added to, and where
+// comments are left on it.
+type TaskDetailsComponent struct {
+	Visible bool
+	Task	*domain.Task
+
+	window		*types.Window
+	board		*domain.Board
+	userConfig	*domain.UserConfig
+	widget		*widgets.Paragraph
+	// selectedItem is the index of the checklist item in focus.
+	selectedItem	int
+	// input is where a new checklist item or a new comment is typed. It's only shown
+	// while adding one, which inputKind says.
+	input			*cw.TextInput
+	inputKind		detailsInputKind
+	// reportError shows the user that a change to the task failed.
+	reportError		func(err error)
+}
+
+// detailsInputKind is what's being typed in the input of the task details.
+type detailsInputKind string
+
+const (
+	noInput				detailsInputKind = ""
+	checklistItemInput	detailsInputKind = "checklist item"
+	commentInput		detailsInputKind = "comment"
+)
+
+func NewTaskDetailsPopupComponent(window *types.Window, board *domain.Board, userConfig *domain.UserConfig, reportError func(err error)) *TaskDetailsComponent {
+	ret := new(TaskDetailsComponent)
+
+	ret.window = window
+	ret.board = board
+	ret.userConfig = userConfig
+	ret.reportError = reportError
+	ret.widget = widgets.NewParagraph()
+	ret.widget.Border = true
+	ret.input = cw.NewTextInput()
+
+	return ret
+}
+
+// SetTask sets the task to show the details of.
+func (self *TaskDetailsComponent) SetTask(task *domain.Task) {
+	self.Task = task
+	self.selectedItem = 0
+}
+
+// RebindTask points the task shown to the one with the same ID on the board, like
+// after the board was reloaded. It hides the popup if the task isn't there anymore.
+func (self *TaskDetailsComponent) RebindTask() {
+	if self.Task == nil {
+		return
+	}
+
+	taskOpt := self.board.GetTaskById(self.Task.Id)
+	if taskOpt.IsNone() {
+		self.Hide()
+		return
+	}
+
+	self.Task = taskOpt.Unwrap()
+	self.selectedItem = max(min(self.selectedItem, len(self.Task.Checklist) - 1), 0)
+}
+
+// HandleInput handles keyboard inputs sent to this component. It returns a boolean
+// indicating if we should clear before we re-render.
+func (self *TaskDetailsComponent) HandleInput(event termui.Event) bool {
+	if self.inputKind != noInput {
+		return self.handleInputKeys(event)
+	}
+
+	switch event.ID {
+	case "j", "<Down>", "<C-n>":
+		self.selectedItem = min(self.selectedItem + 1, max(len(self.Task.Checklist) - 1, 0))
+
+	case "k", "<Up>", "<C-p>":
+		self.selectedItem = max(self.selectedItem - 1, 0)
+
+	case "<Space>", "x":
+		item := self.getSelectedItem()
+		if item == nil {
+			return false
+		}
+
+		err := self.userConfig.ToggleChecklistItem(self.board.Id, self.Task, item.Id)
+		if err != nil {
+			self.reportError(err)
+		}
+
+	case "n":
+		self.inputKind = checklistItemInput
+		self.input.Flush()
+
+	case "c":
+		self.inputKind = commentInput
+		self.input.Flush()
+
+	case "p":
+		item := self.getSelectedItem()
+		if item == nil {
+			return false
+		}
+
+		_, err := self.userConfig.PromoteChecklistItem(self.board.Id, self.Task, item.Id)
+		if err != nil {
+			self.reportError(err)
+		}
+		self.RebindTask()
+		return true
+
+	case "D":
+		item := self.getSelectedItem()
+		if item == nil {
+			return false
+		}
+
+		err := self.userConfig.RemoveChecklistItem(self.board.Id, self.Task, item.Id)
+		if err != nil {
+			self.reportError(err)
+		}
+		self.RebindTask()
+		return true
+
+	case "q", "<Escape>", "<C-c>", "<Enter>":
+		self.Hide()
+		return true
+	}
+
+	return false
+}
+
+// handleInputKeys sends the keys to the input of a new checklist item or comment,
+// adding it on <Enter>.
+func (self *TaskDetailsComponent) handleInputKeys(event termui.Event) bool {
+	switch event.ID {
+	case "<Escape>", "<C-c>":
+		self.inputKind = noInput
+		return true
+
+	case "<Enter>":
+		inputKind := self.inputKind
+		self.inputKind = noInput
+
+		if strings.TrimSpace(self.input.GetText()) == "" {
+			return true
+		}
+
+		var err error
+		if inputKind == checklistItemInput {
+			_, err = self.userConfig.AddChecklistItem(self.board.Id, self.Task, self.input.GetText())
+			self.selectedItem = max(len(self.Task.Checklist) - 1, 0)
+		} else {
+			_, err = self.userConfig.AddComment(self.board.Id, self.Task, domain.GetDefaultCommentAuthor(), self.input.GetText())
+		}
+		if err != nil {
+			self.reportError(err)
+		}
+		return true
+
+	default:
+		self.input.HandleInput(event.ID, true)
+	}
+
+	return false
+}
+
+func (self *TaskDetailsComponent) getSelectedItem() *domain.ChecklistItem {
+	if self.selectedItem < 0 || self.selectedItem >= len(self.Task.Checklist) {
+		return nil
+	}
+
+	return self.Task.Checklist[self.selectedItem]
+}
+
+func (self *TaskDetailsComponent) Hide() {
+	self.Visible = false
+	self.Task = nil
+	self.inputKind = noInput
+}
+
+func (self *TaskDetailsComponent) Show() {
+	self.Visible = true
+}
+
+func (self *TaskDetailsComponent) Draw() {
+	if self.Task == nil {
+		return
+	}
+
+	column, _ := self.board.GetColumnForTask(self.Task)
+
+	self.widget.Title = self.Task.Title
+	self.widget.BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+
+	x1 := self.window.Width / 6
+	y1 := self.window.Height / 6
+	x2 := self.window.Width / 6 * 5
+	y2 := self.window.Height / 6 * 5
+
+	self.widget.SetRect(x1, y1, x2, y2)
+
+	text := fmt.Sprintf("Key: %s\n", self.board.GetTaskKey(self.Task))
+	text += fmt.Sprintf("ID: %s\n", self.Task.Id)
+	text += fmt.Sprintf("Column: %s\n", column.Name)
+	text += fmt.Sprintf("Created at: %s\n", utils.FormatTime(self.Task.CreatedAt))
+	text += fmt.Sprintf("Updated at: %s\n", utils.FormatTime(self.Task.UpdatedAt))
+	if self.Task.StartedAt != nil {
+		text += fmt.Sprintf("Started at: %s\n", utils.FormatTime(*self.Task.StartedAt))
+	}
+	if self.Task.CompletedAt != nil {
+		text += fmt.Sprintf("Completed at: %s\n", utils.FormatTime(*self.Task.CompletedAt))
+	}
+
+	if self.Task.Priority != domain.PriorityNone {
+		text += fmt.Sprintf("Priority: %s\n", self.Task.Priority)
+	}
+	if self.Task.DueDate != "" {
+		text += fmt.Sprintf("Due date: %s%s\n", self.Task.DueDate, utils.Cond(self.board.IsOverdue(self.Task), " (overdue)", ""))
+	}
+	if self.Task.Estimate != nil {
+		text += fmt.Sprintf("Estimate: %s\n", self.Task.Estimate)
+	}
+	if self.Task.Assignee != "" {
+		text += fmt.Sprintf("Assignee: %s\n", self.Task.Assignee)
+	}
+	if len(self.Task.Labels) > 0 {
+		text += fmt.Sprintf("Labels: %s\n", strings.Join(self.Task.Labels, ", "))
+	}
+	if self.Task.Epic != "" {
+		text += fmt.Sprintf("Epic: %s\n", self.Task.Epic)
+	}
+
+	if self.Task.Recurrence != nil {
+		text += fmt.Sprintf("Repeats: %s\n", self.Task.Recurrence)
+	}
+	if key, shelf := self.board.GetOccurrenceKey(self.Task.PreviousOccurrenceId); key != "" {
+		text += fmt.Sprintf("Previous occurrence: %s%s\n", key, utils.Cond(shelf == domain.OnBoard, "", " (in the " + string(shelf) + ")"))
+	}
+	if key, shelf := self.board.GetOccurrenceKey(self.Task.NextOccurrenceId); key != "" {
+		text += fmt.Sprintf("Next occurrence: %s%s\n", key, utils.Cond(shelf == domain.OnBoard, "", " (in the " + string(shelf) + ")"))
+	}
+
+	if blockers := self.board.GetBlockers(self.Task); len(blockers) > 0 {
+		text += "Blocked by:\n"
+		for _, blocker := range blockers {
+			text += "  " + self.describeDependency(blocker) + "\n"
+		}
+	}
+	if dependents := self.board.GetDependents(self.Task); len(dependents) > 0 {
+		text += "Blocks:\n"
+		for _, dependent := range dependents {
+			text += "  " + self.describeDependency(dependent) + "\n"
+		}
+	}
+
+	if self.Task.Description != "" {
+		text += "\n" + self.Task.Description + "\n"
+	}
+
+	done, total := self.Task.GetChecklistProgress()
+	text += fmt.Sprintf("\nChecklist %d/%d (n add, <Space> check, p promote to a task, D remove):\n", done, total)
+	for i, item := range self.Task.Checklist {
+		line := fmt.Sprintf("[%s] %s", utils.Cond(item.Done, "x", " "), item.Text)
+
+		if i == self.selectedItem {
+			line = "[" + line + "](fg:black,bg:white)"
+		}
+
+		text += "  " + line + "\n"
+	}
+
+	text += "\nComments (c add):\n"
+	for _, comment := range self.Task.Comments {
+		text += fmt.Sprintf("  %s, %s:\n", comment.Author, utils.FormatTime(comment.At))
+		text += "    " + comment.Text + "\n"
+	}
+
+	text += "\nHistory:\n"
+	for _, event := range self.Task.GetEvents() {
+		text += fmt.Sprintf("  %s  %s\n", utils.FormatTime(event.At), event.String())
+	}
+
+	self.widget.Text = text
+
+	termui.Render(
+		self.widget,
+	)
+
+	if self.inputKind != noInput {
+		self.input.GetDrawableWidget().Title = fmt.Sprintf("New %s (<Enter> add, <Escape> cancel)", self.inputKind)
+		self.input.GetDrawableWidget().BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+		self.input.GetDrawableWidget().SetRect(x1 + 1, y2 - 4, x2 - 1, y2 - 1)
+
+		termui.Render(
+			self.input.GetDrawableWidget(),
+		)
+	}
+}
+
+// describeDependency describes a task the shown task is blocked by or blocks, in a line.
+func (self *TaskDetailsComponent) describeDependency(task *domain.Task) string {
+	column, _ := self.board.GetColumnForTask(task)
+
+	return fmt.Sprintf("%s  %s (%s)", self.board.GetTaskKey(task), task.Title, column.Name)
+}
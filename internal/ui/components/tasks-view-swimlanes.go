@@ -0,0 +1,291 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/utils"
+)
+
+// isShowingLanes reports if the board is split into swimlanes.
+func (self *TasksViewComponent) isShowingLanes() bool {
+	return self.board.Swimlanes != domain.NoSwimlanes
+}
+
+// getLaneId returns what the lane with the given key is remembered by, like when it's
+// collapsed. Keys are only unique for the swimlanes they're of.
+func (self *TasksViewComponent) getLaneId(key string) string {
+	return string(self.board.Swimlanes) + ":" + key
+}
+
+// getLaneTasks returns the tasks of the column that are in the lane with the given key,
+// in the order they're shown.
+func (self *TasksViewComponent) getLaneTasks(columnId string, key string) []*domain.Task {
+	ret := []*domain.Task{}
+
+	for _, task := range self.getFilteredTasks(columnId) {
+		if self.board.GetSwimlaneKey(task) == key {
+			ret = append(ret, task)
+		}
+	}
+
+	return ret
+}
+
+// getTasksInLaneOfFocus returns the tasks of the column that are in the same lane as
+// the task in focus, or all of them if the board isn't split into swimlanes.
+func (self *TasksViewComponent) getTasksInLaneOfFocus(columnId string) []*domain.Task {
+	if !self.isShowingLanes() || self.TaskInFocus == nil {
+		return self.getFilteredTasks(columnId)
+	}
+
+	return self.getLaneTasks(columnId, self.board.GetSwimlaneKey(self.TaskInFocus))
+}
+
+// getShownLanes returns the lanes of the board that have tasks left once the filter
+// is applied.
+func (self *TasksViewComponent) getShownLanes() []*domain.Swimlane {
+	ret := []*domain.Swimlane{}
+
+	for _, lane := range self.board.GetSwimlanes() {
+		for _, column := range self.board.Columns {
+			if len(self.getLaneTasks(column.Id, lane.Key)) > 0 {
+				ret = append(ret, lane)
+				break
+			}
+		}
+	}
+
+	return ret
+}
+
+// getExpandedLanes returns the shown lanes that aren't collapsed.
+func (self *TasksViewComponent) getExpandedLanes() []*domain.Swimlane {
+	ret := []*domain.Swimlane{}
+
+	for _, lane := range self.getShownLanes() {
+		if !self.collapsedLanes[self.getLaneId(lane.Key)] {
+			ret = append(ret, lane)
+		}
+	}
+
+	return ret
+}
+
+// getNavigableTasks returns the tasks of the column that moving up and down goes
+// through. With swimlanes, these are the tasks of the column in every expanded lane,
+// one lane after the other.
+func (self *TasksViewComponent) getNavigableTasks(columnId string) []*domain.Task {
+	if !self.isShowingLanes() {
+		return self.getFilteredTasks(columnId)
+	}
+
+	ret := []*domain.Task{}
+	for _, lane := range self.getExpandedLanes() {
+		ret = append(ret, self.getLaneTasks(columnId, lane.Key)...)
+	}
+
+	return ret
+}
+
+// focusFirstTaskInLanes sets the focus to the first task of the first of the given
+// lanes, preferring the column of the task in focus. It returns false if the lanes
+// have no tasks.
+func (self *TasksViewComponent) focusFirstTaskInLanes(lanes []*domain.Swimlane) bool {
+	columnIds := []string{}
+	if self.TaskInFocus != nil {
+		if column, _ := self.board.GetColumnForTask(self.TaskInFocus); column != nil {
+			columnIds = append(columnIds, column.Id)
+		}
+	}
+	for _, column := range self.board.Columns {
+		columnIds = append(columnIds, column.Id)
+	}
+
+	for _, lane := range lanes {
+		for _, columnId := range columnIds {
+			tasks := self.getLaneTasks(columnId, lane.Key)
+			if len(tasks) > 0 {
+				self.TaskInFocus = tasks[0]
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// focusAdjacentLane sets the focus to the first task of the next expanded lane for a
+// step of 1, or of the previous one for a step of -1.
+func (self *TasksViewComponent) focusAdjacentLane(step int) bool {
+	if self.TaskInFocus == nil {
+		return false
+	}
+
+	lanes := self.getExpandedLanes()
+	key := self.board.GetSwimlaneKey(self.TaskInFocus)
+	i := slices.IndexFunc(lanes, func(lane *domain.Swimlane) bool { return lane.Key == key })
+	if i == -1 {
+		return false
+	}
+
+	for i += step; i >= 0 && i < len(lanes); i += step {
+		if self.focusFirstTaskInLanes([]*domain.Swimlane{lanes[i]}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// collapseLaneOfFocus collapses the lane of the task in focus down to its header,
+// moving the focus to the next lane, or to the previous one if it's the last.
+func (self *TasksViewComponent) collapseLaneOfFocus() {
+	if !self.isShowingLanes() || self.TaskInFocus == nil {
+		return
+	}
+
+	laneId := self.getLaneId(self.board.GetSwimlaneKey(self.TaskInFocus))
+
+	if !self.focusAdjacentLane(1) && !self.focusAdjacentLane(-1) {
+		self.TaskInFocus = nil
+	}
+
+	self.collapsedLanes[laneId] = true
+}
+
+// moveTaskInFocusWithinLane moves the task in focus up or down among the tasks of its
+// lane on "K" and "J", or to the top or the bottom of them on "T" and "B". The tasks of
+// other lanes in the column stay where they are.
+func (self *TasksViewComponent) moveTaskInFocusWithinLane(key string) {
+	column, _ := self.board.GetColumnForTask(self.TaskInFocus)
+	if column == nil {
+		return
+	}
+
+	tasks := self.getTasksInLaneOfFocus(column.Id)
+	i := slices.Index(tasks, self.TaskInFocus)
+	if i == -1 {
+		return
+	}
+
+	target := i
+	switch key {
+	case "K":
+		target = max(i - 1, 0)
+	case "J":
+		target = min(i + 1, len(tasks) - 1)
+	case "T":
+		target = 0
+	case "B":
+		target = len(tasks) - 1
+	}
+
+	if target == i {
+		return
+	}
+
+	// Taking the place of the task it's moved to puts it right above that task when
+	// going up, and right below it when going down.
+	position := self.board.GetTaskPosition(tasks[target])
+	err := self.userConfig.MoveTaskWithinColumn(self.board, self.TaskInFocus, position)
+	if err != nil {
+		self.reportError(fmt.Errorf("Failed to move the task within its lane. %s", err))
+	}
+}
+
+// drawLanes lays the cards out in a row for every lane, each under a header that
+// crosses all the columns. Lanes are scrolled so the task in focus is shown.
+func (self *TasksViewComponent) drawLanes(widgetWidth int) []*widgets.Paragraph {
+	lanes := self.getShownLanes()
+
+	focusedLane := -1
+	if self.TaskInFocus != nil {
+		key := self.board.GetSwimlaneKey(self.TaskInFocus)
+		focusedLane = slices.IndexFunc(lanes, func(lane *domain.Swimlane) bool { return lane.Key == key })
+	}
+
+	self.scroll = min(self.scroll, max(len(lanes) - 1, 0))
+	if focusedLane != -1 && focusedLane < self.scroll {
+		self.scroll = focusedLane
+	}
+
+	ret, focusedBottom := self.layoutLanes(lanes[min(self.scroll, len(lanes)):], widgetWidth)
+
+	// Scroll down a lane at a time until the card in focus fits, as long as its lane
+	// stays in view.
+	for focusedBottom > self.window.Height && self.scroll < focusedLane {
+		self.scroll += 1
+		ret, focusedBottom = self.layoutLanes(lanes[self.scroll:], widgetWidth)
+	}
+
+	return ret
+}
+
+// layoutLanes places the header and the cards of every given lane one after the other,
+// starting right under the headers of the columns. It returns the widgets along with
+// where the card in focus ends, or 0 if it's not in any of the lanes.
+func (self *TasksViewComponent) layoutLanes(lanes []*domain.Swimlane, widgetWidth int) ([]*widgets.Paragraph, int) {
+	ret := []*widgets.Paragraph{}
+	focusedBottom := 0
+
+	y := 3 // 3 here is the y length of the header.
+
+	for _, lane := range lanes {
+		collapsed := self.collapsedLanes[self.getLaneId(lane.Key)]
+
+		columnsTasks := [][]*domain.Task{}
+		count := 0
+		for _, column := range self.board.Columns {
+			tasks := self.getLaneTasks(column.Id, lane.Key)
+			columnsTasks = append(columnsTasks, tasks)
+			count += len(tasks)
+		}
+
+		// The header is only the top border of a block, with the name of the lane on it.
+		header := widgets.NewParagraph()
+		header.Border = true
+		header.BorderLeft = false
+		header.BorderRight = false
+		header.BorderBottom = false
+		header.BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+		header.Title = fmt.Sprintf(" %s %s (%d) ", utils.Cond(collapsed, "▸", "▾"), lane.Name, count)
+		header.TitleStyle = termui.NewStyle(self.userConfig.PrimaryColor, termui.ColorClear, termui.ModifierBold)
+		header.SetRect(0, y, self.window.Width, y + 1)
+
+		ret = append(ret, header)
+		y += 1
+
+		if collapsed {
+			continue
+		}
+
+		laneHeight := 0
+		for columnIndex, tasks := range columnsTasks {
+			x1 := columnIndex * widgetWidth
+			x2 := x1 + widgetWidth
+			y1 := y
+
+			for _, task := range tasks {
+				widget, widgetLength := self.newTaskWidget(task, widgetWidth)
+				widget.SetRect(x1, y1, x2, y1 + widgetLength)
+
+				if task == self.TaskInFocus {
+					focusedBottom = y1 + widgetLength
+				}
+
+				y1 += widgetLength
+				ret = append(ret, widget)
+			}
+
+			laneHeight = max(laneHeight, y1 - y)
+		}
+
+		y += laneHeight
+	}
+
+	return ret, focusedBottom
+}
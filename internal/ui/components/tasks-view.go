@@ -1,8 +1,9 @@
 package components
 
 import (
-	"log"
+	"fmt"
 	"math"
+	"slices"
 	"strings"
 
 	"github.com/gizak/termui/v3"
@@ -23,21 +24,29 @@ type TasksViewComponent struct {
 	board                 *domain.Board
 	userConfig            *domain.UserConfig
 	filter                opt.Option[string]
+	// scroll is how many tasks of every column are scrolled beyond, or how many lanes
+	// are while the board is split into swimlanes.
 	scroll                int
+	// collapsedLanes holds the swimlanes that only show their header. See getLaneId.
+	collapsedLanes        map[string]bool
 	// goToFirstTaskInColumn Tells the draw function to set the 
 	// the task in focus to be the pointer to the first task
 	// in the column list that is set.
-	// Its value is the name of a column.
+	// Its value is the ID of a column.
 	goToFirstTaskInColumn opt.Option[string]
+	// reportError shows the user that a change to the board failed.
+	reportError           func(err error)
 }
 
-func NewTasksViewComponent(window *types.Window, board *domain.Board, userConfig *domain.UserConfig) *TasksViewComponent {
+func NewTasksViewComponent(window *types.Window, board *domain.Board, userConfig *domain.UserConfig, reportError func(err error)) *TasksViewComponent {
 	ret := new(TasksViewComponent)
 	
 	ret.window = window
 	ret.board = board
 	ret.userConfig = userConfig
+	ret.reportError = reportError
 	ret.tasksWidgets = []*widgets.Paragraph{}
+	ret.collapsedLanes = map[string]bool{}
 	
 	ret.tasksWidgets = ret.drawTasks()
 	
@@ -49,12 +58,16 @@ func NewTasksViewComponent(window *types.Window, board *domain.Board, userConfig
 func (self *TasksViewComponent) HandleKeymap(key string) bool {
 	shouldClear := false
 	
-	if self.TaskInFocus == nil {
+	if !self.isTaskInFocusShown() {
 		self.SetDefaultFocusedWidget()
 	}
 	
-	colName, _ := self.board.GetColumnForTask(self.TaskInFocus)
-	tasks := self.getFilteredTasks(colName)
+	columnId := ""
+	if column, _ := self.board.GetColumnForTask(self.TaskInFocus); column != nil {
+		columnId = column.Id
+	}
+	// With swimlanes, moving up and down goes through the lanes of the column one after the other.
+	tasks := self.getNavigableTasks(columnId)
 	
 	// @Speed: Movement now is an O(n) operation on every key stroke because we use a simple dynamic array
 	// to store tasks for each column. A more sophesticated DS like a Linked List would benefit vertical 
@@ -65,10 +78,11 @@ func (self *TasksViewComponent) HandleKeymap(key string) bool {
 			for i := range tasks {
 				if tasks[i].Id == self.TaskInFocus.Id {
 					// Scroll up one task if you're on a the first task in the view but not in the list.
-					if i == 0 && self.scroll > 0 {
+					// Lanes are scrolled to follow the task in focus instead.
+					if i == 0 && self.scroll > 0 && !self.isShowingLanes() {
 						self.scroll -= 1
 						
-						self.goToFirstTaskInColumn = opt.Some(colName)
+						self.goToFirstTaskInColumn = opt.Some(columnId)
 					} else if i - 1 >= 0 { // If we're not the first task in the list.
 						self.TaskInFocus = tasks[i - 1]
 					}
@@ -105,8 +119,8 @@ func (self *TasksViewComponent) HandleKeymap(key string) bool {
 				return shouldClear
 			}
 			
-			nextColumnName := self.board.Columns[nextColumnIndex]
-			tasksInNextColumn := self.getFilteredTasks(nextColumnName)
+			nextColumnId := self.board.Columns[nextColumnIndex].Id
+			tasksInNextColumn := self.getTasksInLaneOfFocus(nextColumnId)
 			
 			for len(tasksInNextColumn) == 0 {
 				nextColumnIndex += 1
@@ -115,11 +129,11 @@ func (self *TasksViewComponent) HandleKeymap(key string) bool {
 					return shouldClear
 				}
 				
-				nextColumnName = self.board.Columns[nextColumnIndex]
-				tasksInNextColumn = self.getFilteredTasks(nextColumnName)
+				nextColumnId = self.board.Columns[nextColumnIndex].Id
+				tasksInNextColumn = self.getTasksInLaneOfFocus(nextColumnId)
 			}
 			
-			columnToMoveTo = nextColumnName
+			columnToMoveTo = nextColumnId
 		} else {
 			prevColumnIndex := columnIndexForTask - 1
 			
@@ -127,8 +141,8 @@ func (self *TasksViewComponent) HandleKeymap(key string) bool {
 				return shouldClear
 			}
 			
-			prevColumnName := self.board.Columns[prevColumnIndex]
-			tasksInPrevColumn := self.getFilteredTasks(prevColumnName)
+			prevColumnId := self.board.Columns[prevColumnIndex].Id
+			tasksInPrevColumn := self.getTasksInLaneOfFocus(prevColumnId)
 			
 			for len(tasksInPrevColumn) == 0 {
 				prevColumnIndex -= 1
@@ -137,18 +151,24 @@ func (self *TasksViewComponent) HandleKeymap(key string) bool {
 					return shouldClear
 				}
 				
-				prevColumnName = self.board.Columns[prevColumnIndex]
-				tasksInPrevColumn = self.getFilteredTasks(prevColumnName)
+				prevColumnId = self.board.Columns[prevColumnIndex].Id
+				tasksInPrevColumn = self.getTasksInLaneOfFocus(prevColumnId)
 			}
 			
-			columnToMoveTo = prevColumnName
+			columnToMoveTo = prevColumnId
 		}
 		
-		tasksToMoveTo := self.getFilteredTasks(columnToMoveTo)
+		tasksToMoveTo := self.getTasksInLaneOfFocus(columnToMoveTo)
 		
 		self.TaskInFocus = tasksToMoveTo[0]
 
 	case "n":
+		if self.isShowingLanes() {
+			self.focusAdjacentLane(1)
+			shouldClear = true
+			break
+		}
+		
 		// @Todo: This scrolls infinitely for now.
 		self.scroll += 1
 		
@@ -156,7 +176,7 @@ func (self *TasksViewComponent) HandleKeymap(key string) bool {
 		for i := range tasks {
 			if tasks[i] == self.TaskInFocus {
 				if self.scroll > i {
-					self.goToFirstTaskInColumn = opt.Some(colName)
+					self.goToFirstTaskInColumn = opt.Some(columnId)
 				}
 			}
 		}
@@ -164,6 +184,12 @@ func (self *TasksViewComponent) HandleKeymap(key string) bool {
 		shouldClear = true
 		
 	case "p":
+		if self.isShowingLanes() {
+			self.focusAdjacentLane(-1)
+			shouldClear = true
+			break
+		}
+		
 		newScroll := self.scroll - 1
 		
 		if newScroll >= 0 {
@@ -173,49 +199,129 @@ func (self *TasksViewComponent) HandleKeymap(key string) bool {
 		shouldClear = true
 		
 	case "g":
-		columnName, i := self.board.GetColumnForTask(self.TaskInFocus)
+		if self.TaskInFocus == nil {
+			break
+		}
+		
+		column, i := self.board.GetColumnForTask(self.TaskInFocus)
 		if i == -1 {
-			log.Fatalf("Failed to find the columnName for task on scrolling to top.")
+			utils.SaveLog(utils.Warn, "Couldn't find the column of the task in focus on scrolling to the top", nil)
+			break
 		}
 		
-		self.setFocusOnTopTask(columnName)
-		self.scroll = 0
+		if self.isShowingLanes() {
+			// The top most task of the lane rather than of the whole column.
+			self.TaskInFocus = self.getTasksInLaneOfFocus(column.Id)[0]
+		} else {
+			self.setFocusOnTopTask(column.Id)
+			self.scroll = 0
+		}
 		shouldClear = true
 		
 	case "G":
-		columnName, i := self.board.GetColumnForTask(self.TaskInFocus)
+		if self.TaskInFocus == nil {
+			break
+		}
+		
+		column, i := self.board.GetColumnForTask(self.TaskInFocus)
 		if i == -1 {
-			log.Fatalf("Failed to find the columnName for task on scrolling to bottom.")
+			utils.SaveLog(utils.Warn, "Couldn't find the column of the task in focus on scrolling to the bottom", nil)
+			break
 		}
 		
-		self.setFocusOnBottomTask(columnName)
-		self.scroll = len(self.board.Tasks[columnName]) - 1
+		if self.isShowingLanes() {
+			tasks := self.getTasksInLaneOfFocus(column.Id)
+			self.TaskInFocus = tasks[len(tasks) - 1]
+		} else {
+			self.setFocusOnBottomTask(column.Id)
+			self.scroll = len(self.board.Tasks[column.Id]) - 1
+		}
 			
 		shouldClear = true
 		
-	case "]":
-		err := self.userConfig.MoveTaskRight(self.board, self.TaskInFocus)
+	case "K", "J", "T", "B":
+		if self.TaskInFocus == nil {
+			break
+		}
+		
+		if self.isShowingLanes() {
+			self.moveTaskInFocusWithinLane(key)
+			shouldClear = true
+			break
+		}
+		
+		position := self.board.GetTaskPosition(self.TaskInFocus)
+		switch key {
+		case "K":
+			position -= 1
+			// Scroll up along with the task if it was the first one in the view.
+			if len(tasks) > 0 && tasks[0] == self.TaskInFocus && self.scroll > 0 {
+				self.scroll -= 1
+			}
+		case "J":
+			position += 1
+		case "T":
+			position = 0
+			self.scroll = 0
+		case "B":
+			position = len(self.board.Tasks[columnId]) - 1
+		}
+		
+		err := self.userConfig.MoveTaskWithinColumn(self.board, self.TaskInFocus, position)
 		if err != nil {
-			utils.SaveLog(
-				utils.Error, 
-				"Failed to move task to the right. " + err.Error(), 
-				map[string]any{
-					"task": self.TaskInFocus.Title,
-				},
-			)
+			self.reportError(fmt.Errorf("Failed to move the task within its column. %s", err))
 		}
 		shouldClear = true
 		
-	case "[":
-		err := self.userConfig.MoveTaskLeft(self.board, self.TaskInFocus)
+	case "z":
+		self.collapseLaneOfFocus()
+		shouldClear = true
+		
+	case "Z":
+		self.collapsedLanes = map[string]bool{}
+		shouldClear = true
+		
+	case "A":
+		err := self.userConfig.SetShowTaskAges(self.board.Id, !self.board.ShowTaskAges)
 		if err != nil {
-			utils.SaveLog(
-				utils.Error, 
-				"Failed to move task to the left. " + err.Error(), 
-				map[string]any{
-					"task": self.TaskInFocus.Title,
-				},
-			)
+			self.reportError(fmt.Errorf("Failed to show the ages of the tasks of the board. %s", err))
+		}
+		shouldClear = true
+		
+	case "S":
+		// Cycles through the ways to split the board into lanes, and back to none.
+		i := slices.Index(domain.SwimlaneGroupings, self.board.Swimlanes)
+		grouping := domain.SwimlaneGroupings[(i + 1) % len(domain.SwimlaneGroupings)]
+		
+		err := self.userConfig.SetSwimlanes(self.board.Id, grouping)
+		if err != nil {
+			self.reportError(fmt.Errorf("Failed to set the swimlanes of the board. %s", err))
+		}
+		self.scroll = 0
+		shouldClear = true
+		
+	case "]", "}":
+		// "}" moves the task to the bottom of the next column rather than on top of it.
+		if self.TaskInFocus == nil {
+			break
+		}
+		
+		placement := utils.Cond(key == "}", domain.PlaceAtBottom, domain.PlaceOnTop)
+		err := self.userConfig.MoveTaskRight(self.board, self.TaskInFocus, placement)
+		if err != nil {
+			self.reportError(fmt.Errorf("Failed to move the task to the right. %s", err))
+		}
+		shouldClear = true
+		
+	case "[", "{":
+		if self.TaskInFocus == nil {
+			break
+		}
+		
+		placement := utils.Cond(key == "{", domain.PlaceAtBottom, domain.PlaceOnTop)
+		err := self.userConfig.MoveTaskLeft(self.board, self.TaskInFocus, placement)
+		if err != nil {
+			self.reportError(fmt.Errorf("Failed to move the task to the left. %s", err))
 		}
 		shouldClear = true
 		
@@ -236,18 +342,24 @@ func (self *TasksViewComponent) SetDefaultFocusedWidget() {
 		return
 	}
 	
+	if self.isShowingLanes() {
+		self.TaskInFocus = nil
+		self.focusFirstTaskInLanes(self.getExpandedLanes())
+		return
+	}
+	
 	// Set the task in focus to be the first task you encounter (doesn't necessarily mean the first column.)
 	found := false
-	for _, columnName := range self.board.Columns {
+	for _, column := range self.board.Columns {
 		if found {
 			break
 		}
 		
-		if _, ok := self.board.Tasks[columnName]; !ok {
+		if _, ok := self.board.Tasks[column.Id]; !ok {
 			continue
 		}
 		
-		tasks := self.getFilteredTasks(columnName)
+		tasks := self.getFilteredTasks(column.Id)
 		
 		for _, task := range tasks {
 			self.TaskInFocus = task
@@ -257,6 +369,117 @@ func (self *TasksViewComponent) SetDefaultFocusedWidget() {
 	}
 }
 
+// FocusTaskById sets the focus to the task with the given ID, or to the default
+// task if it's not on the board anymore.
+func (self *TasksViewComponent) FocusTaskById(taskId string) {
+	taskOpt := self.board.GetTaskById(taskId)
+	if taskOpt.IsNone() {
+		self.SetDefaultFocusedWidget()
+		return
+	}
+	
+	self.TaskInFocus = taskOpt.Unwrap()
+	
+	// Tasks scrolled beyond aren't drawn, so scroll back to the top if it's one of them.
+	column, _ := self.board.GetColumnForTask(self.TaskInFocus)
+	tasks := self.board.Tasks[column.Id]
+	for i, task := range tasks {
+		if task == self.TaskInFocus && len(tasks) - 1 - i < self.scroll {
+			self.scroll = 0
+		}
+	}
+}
+
+// getTaskMetadataText returns the line under the title of the card, like
+// "blocked  3/5  due 2024-05-01  @omar  #api", along with its length without styling.
+func (self *TasksViewComponent) getTaskMetadataText(task *domain.Task) (string, int) {
+	parts := []string{}
+	styledParts := []string{}
+	
+	if self.board.IsBlocked(task) {
+		parts = append(parts, "blocked")
+		styledParts = append(styledParts, "[blocked](fg:magenta,mod:bold)")
+	}
+	
+	if done, total := task.GetChecklistProgress(); total > 0 {
+		progress := fmt.Sprintf("%d/%d", done, total)
+		
+		parts = append(parts, progress)
+		styledParts = append(styledParts, utils.Cond(done == total, "[" + progress + "](fg:green)", progress))
+	}
+	
+	if task.DueDate != "" {
+		due := "due " + task.DueDate
+		
+		if self.board.IsOverdue(task) {
+			due += " (overdue)"
+			styledParts = append(styledParts, "[" + due + "](fg:red,mod:bold)")
+		} else {
+			styledParts = append(styledParts, due)
+		}
+		parts = append(parts, due)
+	}
+	
+	if task.Estimate != nil {
+		parts = append(parts, task.Estimate.String())
+		styledParts = append(styledParts, task.Estimate.String())
+	}
+	
+	if task.Assignee != "" {
+		parts = append(parts, "@" + task.Assignee)
+		styledParts = append(styledParts, "@" + task.Assignee)
+	}
+	
+	for _, label := range task.Labels {
+		parts = append(parts, "#" + label)
+		styledParts = append(styledParts, "#" + label)
+	}
+	
+	if task.Epic != "" {
+		parts = append(parts, "epic " + task.Epic)
+		styledParts = append(styledParts, "epic " + task.Epic)
+	}
+	
+	if task.Recurrence != nil {
+		parts = append(parts, "repeats " + task.Recurrence.String())
+		styledParts = append(styledParts, "repeats " + task.Recurrence.String())
+	}
+	
+	if self.board.ShowTaskAges {
+		age := utils.FormatAge(self.board.GetAge(task))
+		if task.CompletedAt != nil && self.board.IsDone(task) {
+			age = "done " + age + utils.Cond(age == "now", "", " ago")
+		}
+		
+		parts = append(parts, age)
+		styledParts = append(styledParts, age)
+	}
+	
+	return strings.Join(styledParts, "  "), len(strings.Join(parts, "  "))
+}
+
+// isTaskInFocusOnBoard checks that there's a task in focus and that it's still on
+// the board. Saving can merge in changes from another process that removed it.
+func (self *TasksViewComponent) isTaskInFocusOnBoard() bool {
+	if self.TaskInFocus == nil {
+		return false
+	}
+	
+	_, columnIndex := self.board.GetColumnForTask(self.TaskInFocus)
+	
+	return columnIndex != -1
+}
+
+// isTaskInFocusShown checks that the task in focus is on the board and that it's
+// not in a collapsed lane.
+func (self *TasksViewComponent) isTaskInFocusShown() bool {
+	if !self.isTaskInFocusOnBoard() {
+		return false
+	}
+	
+	return !self.isShowingLanes() || !self.collapsedLanes[self.getLaneId(self.board.GetSwimlaneKey(self.TaskInFocus))]
+}
+
 // SetTextFilter applies a searching phase to the state.
 func (self *TasksViewComponent) SetTextFilter(filter string) {
 	self.filter = utils.Cond(filter == "", opt.None[string](), opt.Some(filter))
@@ -267,19 +490,34 @@ func (self *TasksViewComponent) SetTextFilter(filter string) {
 // for the current scroll value (therefore tasks that we scrolled beyond aren't even 
 // accounted for, or rendered) as well as filtered if a filter is in effect. 
 // They are filtered because in the board we should show the last added task first.
-func (self *TasksViewComponent) getFilteredTasks(columnName string) []*domain.Task {
+func (self *TasksViewComponent) getFilteredTasks(columnId string) []*domain.Task {
 	ret := []*domain.Task{}
+	
+	// Lanes are scrolled as a whole rather than by the tasks of every column.
+	scroll := utils.Cond(self.isShowingLanes(), 0, self.scroll)
 
-	for i := (len(self.board.Tasks[columnName]) - 1 - self.scroll); i >= 0; i -= 1 {
-		task := self.board.Tasks[columnName][i]
+	for i := (len(self.board.Tasks[columnId]) - 1 - scroll); i >= 0; i -= 1 {
+		task := self.board.Tasks[columnId][i]
 		
 		// If a filter is provided, make sure to only draw the tasks that match the searched for phrase
 		// by skipping the ones that don't.
 		if self.filter.IsSome() {
 			title := strings.ToLower(task.Title)
 			desc := strings.ToLower(task.Description)
+			// The key, the labels, the assignee and the epic are matched as they're shown,
+			// like "api-42", "#api", "@omar" or "epic payments".
+			tags := strings.ToLower(self.board.GetTaskKey(task)) + " "
+			if task.Assignee != "" {
+				tags += "@" + strings.ToLower(task.Assignee) + " "
+			}
+			for _, label := range task.Labels {
+				tags += "#" + strings.ToLower(label) + " "
+			}
+			if task.Epic != "" {
+				tags += "epic " + strings.ToLower(task.Epic) + " "
+			}
 			
-			if !utils.IncludesFuzzy(title, self.filter.Unwrap()) && !utils.IncludesFuzzy(desc, self.filter.Unwrap()) {
+			if !utils.IncludesFuzzy(title, self.filter.Unwrap()) && !utils.IncludesFuzzy(desc, self.filter.Unwrap()) && !utils.IncludesFuzzy(tags, self.filter.Unwrap()) {
 				continue
 			}
 		}
@@ -290,39 +528,38 @@ func (self *TasksViewComponent) getFilteredTasks(columnName string) []*domain.Ta
 	return ret
 }
 
-func (self *TasksViewComponent) setFocusOnTopTask(columnName string) {
-	if _, ok := self.board.Tasks[columnName]; !ok {
+func (self *TasksViewComponent) setFocusOnTopTask(columnId string) {
+	if _, ok := self.board.Tasks[columnId]; !ok {
 		return
 	}
 	
-	len := len(self.board.Tasks[columnName])
+	len := len(self.board.Tasks[columnId])
 	
 	if len == 0 {
 		return
 	}
 	
-	self.TaskInFocus = self.board.Tasks[columnName][len - 1]
+	self.TaskInFocus = self.board.Tasks[columnId][len - 1]
 }
 
-func (self *TasksViewComponent) setFocusOnBottomTask(columnName string) {
-	if _, ok := self.board.Tasks[columnName]; !ok {
+func (self *TasksViewComponent) setFocusOnBottomTask(columnId string) {
+	if _, ok := self.board.Tasks[columnId]; !ok {
 		return
 	}
 	
-	if len(self.board.Tasks[columnName]) == 0 {
+	if len(self.board.Tasks[columnId]) == 0 {
 		return
 	}
 	
-	self.TaskInFocus = self.board.Tasks[columnName][0]
+	self.TaskInFocus = self.board.Tasks[columnId][0]
 }
 
 func (self *TasksViewComponent) drawTasks() []*widgets.Paragraph {
 	ret := []*widgets.Paragraph{}
 	
 	widgetWidth := self.window.Width / len(self.board.Columns)
-	const widthPadding = 4
 
-	if self.TaskInFocus == nil {
+	if !self.isTaskInFocusShown() {
 		self.SetDefaultFocusedWidget()
 	}
 	
@@ -335,8 +572,8 @@ func (self *TasksViewComponent) drawTasks() []*widgets.Paragraph {
 
 		// Set the task in focus to be the first task you encounter (doesn't necessarily mean the first column.)
 		found := false
-		for _, columnName := range self.board.Columns {
-			if columnName != self.goToFirstTaskInColumn.Unwrap() {
+		for _, column := range self.board.Columns {
+			if column.Id != self.goToFirstTaskInColumn.Unwrap() {
 				continue
 			}
 			
@@ -344,7 +581,7 @@ func (self *TasksViewComponent) drawTasks() []*widgets.Paragraph {
 				break
 			}
 			
-			tasks := self.getFilteredTasks(columnName)
+			tasks := self.getFilteredTasks(column.Id)
 			
 			for _, task := range tasks {
 				self.TaskInFocus = task
@@ -356,60 +593,17 @@ func (self *TasksViewComponent) drawTasks() []*widgets.Paragraph {
 	self.goToFirstTaskInColumn = opt.None[string]()
 	
 	
-	for columnIndex, columnName := range self.board.Columns {
+	if self.isShowingLanes() {
+		return self.drawLanes(widgetWidth)
+	}
+	
+	for columnIndex, column := range self.board.Columns {
 		differentWidgetsLengths := []int{}
 		
-		tasks := self.getFilteredTasks(columnName)
+		tasks := self.getFilteredTasks(column.Id)
 		
 		for _, task := range tasks {
-			widgetLength := 2 // Border lines.
-
-			widgetLength += int(math.Ceil(
-				float64(len(task.Title)) / float64(widgetWidth-2),
-			))
-
-			if task.Description != "" {
-				widgetLength += 1 // The separator line "-------" between the title and the description
-				widgetLength += int(math.Ceil(
-					float64(len(task.Description)) / float64(widgetWidth-2), // 2 here is for border lines
-				))
-			}
-
-			// Set a minimum length size for every task.
-			if widgetLength < 6 {
-				widgetLength = 6
-			}
-
-			widget := widgets.NewParagraph()
-			widget.Border = true
-			
-			if task == self.TaskInFocus{
-				widget.BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
-			}
-			
-			widget.WrapText = true
-			// widget.Text = TextEllipsis(ticket.Title, (widgetWidth - widthPadding))
-			widget.Text = task.Title
-			widget.Text += "\n"
-			widget.Text += strings.Repeat("-", widgetWidth-widthPadding)
-			widget.Text += "\n"
-
-			if task.Description != "" {
-				widget.Text += task.Description
-			} else {
-				// See how much the title has taken up. If it took only one line, add a new line to the description
-				// because it looks better.
-				if math.Ceil(
-					float64(len(task.Title))/float64(widgetWidth),
-				) == 1 {
-					widget.Text += "\n"
-				}
-
-				widget.Text += utils.CenterText("No description found.", widgetWidth, true)
-			}
-
-			widget.PaddingLeft = 1
-			widget.PaddingRight = 1
+			widget, widgetLength := self.newTaskWidget(task, widgetWidth)
 
 			x1 := columnIndex * widgetWidth
 			x2 := x1 + widgetWidth
@@ -433,6 +627,84 @@ func (self *TasksViewComponent) drawTasks() []*widgets.Paragraph {
 	return ret
 }
 
+// newTaskWidget builds the card of the task for a column of the given width. It
+// returns the card along with its height, leaving it to the caller to place it.
+func (self *TasksViewComponent) newTaskWidget(task *domain.Task, widgetWidth int) (*widgets.Paragraph, int) {
+	const widthPadding = 4
+	
+	widgetLength := 2 // Border lines.
+
+	widgetLength += int(math.Ceil(
+		float64(len(task.Title)) / float64(widgetWidth-2),
+	))
+
+	metadata, metadataLength := self.getTaskMetadataText(task)
+	if metadataLength > 0 {
+		widgetLength += int(math.Ceil(
+			float64(metadataLength) / float64(widgetWidth-2),
+		))
+	}
+
+	if task.Description != "" {
+		widgetLength += 1 // The separator line "-------" between the title and the description
+		widgetLength += int(math.Ceil(
+			float64(len(task.Description)) / float64(widgetWidth-2), // 2 here is for border lines
+		))
+	}
+
+	// Set a minimum length size for every task.
+	if widgetLength < 6 {
+		widgetLength = 6
+	}
+
+	widget := widgets.NewParagraph()
+	widget.Border = true
+	
+	if task == self.TaskInFocus{
+		widget.BorderStyle = termui.NewStyle(self.userConfig.PrimaryColor)
+	}
+	
+	// The key and the priority go on the top border, standing out if the
+	// priority is high.
+	widget.Title = self.board.GetTaskKey(task)
+	if task.Priority != domain.PriorityNone {
+		widget.Title += " " + string(task.Priority)
+		if task.IsHighPriority() {
+			widget.TitleStyle = termui.NewStyle(termui.ColorRed, termui.ColorClear, termui.ModifierBold)
+		}
+	}
+	
+	widget.WrapText = true
+	// widget.Text = TextEllipsis(ticket.Title, (widgetWidth - widthPadding))
+	widget.Text = task.Title
+	widget.Text += "\n"
+	if metadataLength > 0 {
+		widget.Text += metadata
+		widget.Text += "\n"
+	}
+	widget.Text += strings.Repeat("-", widgetWidth-widthPadding)
+	widget.Text += "\n"
+
+	if task.Description != "" {
+		widget.Text += task.Description
+	} else {
+		// See how much the title has taken up. If it took only one line, add a new line to the description
+		// because it looks better.
+		if math.Ceil(
+			float64(len(task.Title))/float64(widgetWidth),
+		) == 1 {
+			widget.Text += "\n"
+		}
+
+		widget.Text += utils.CenterText("No description found.", widgetWidth, true)
+	}
+
+	widget.PaddingLeft = 1
+	widget.PaddingRight = 1
+	
+	return widget, widgetLength
+}
+
 func (self *TasksViewComponent) GetAllDrawableWidgets() []termui.Drawable {
 	ret := []termui.Drawable{}
 	
@@ -452,13 +724,14 @@ func (self *TasksViewComponent) Draw() {
 	)
 }
 
-// FocusLatestTaskInFirstColumn sets the focus to the latest task in the leftmost column.
-func (self *TasksViewComponent) FocusLatestTaskInFirstColumn() {
+// FocusLatestTaskInBacklogColumn sets the focus to the latest task in the column new
+// tasks are added to.
+func (self *TasksViewComponent) FocusLatestTaskInBacklogColumn() {
 	if len(self.board.Columns) == 0 {
 		return
 	}
-	columnName := self.board.Columns[0]
-	tasks := self.board.Tasks[columnName]
+	columnId := self.board.GetBacklogColumn().Id
+	tasks := self.board.Tasks[columnId]
 	if len(tasks) == 0 {
 		return
 	}
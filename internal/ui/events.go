@@ -1,9 +1,14 @@
 package ui
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/gizak/termui/v3"
+	"github.com/okira-e/gotasks/internal/domain"
+	"github.com/okira-e/gotasks/internal/opt"
+	"github.com/okira-e/gotasks/internal/utils"
 )
 
 // handleEvent processes user input and other events.
@@ -16,6 +21,9 @@ func (app *App) handleEvent(event termui.Event) {
 		
 	} else if event.Type == termui.KeyboardEvent {
 		shouldClear = app.handleKeymap(event)
+		
+	} else if event.Type == BoardChangedEvent {
+		shouldClear = app.handleBoardChanged()
 	}
 	
 	app.render(shouldClear)
@@ -29,13 +37,17 @@ func (app *App) handleEvent(event termui.Event) {
 func (app *App) handleKeymap(event termui.Event) bool {
 	shouldClear := false
 
-	if app.createTaskPopup.Visible {
+	// Notices go on top of everything else, so they're the first to take a key.
+	if app.notificationPopup.Visible {
+		shouldClear = app.notificationPopup.HandleInput(event)
+		
+	} else if app.createTaskPopup.Visible {
 		wasVisible := app.createTaskPopup.Visible
 		wasEditing := app.createTaskPopup.EditingTask != nil
 		shouldClear = app.createTaskPopup.HandleKeyboardEvent(event)
 		// If popup just closed and it was a new task (not edit), focus latest
 		if wasVisible && !app.createTaskPopup.Visible && !wasEditing {
-			app.tasksView.FocusLatestTaskInFirstColumn()
+			app.tasksView.FocusLatestTaskInBacklogColumn()
 		}
 		
 	} else if app.confirmationPopup.Visible {
@@ -44,6 +56,28 @@ func (app *App) handleKeymap(event termui.Event) bool {
 	} else if app.searchDialogPopup.Visible {
 		shouldClear = app.searchDialogPopup.HandleInput(event)
 		
+	} else if app.taskDetailsPopup.Visible {
+		shouldClear = app.taskDetailsPopup.HandleInput(event)
+		
+	} else if app.removedTasksBrowser.Visible {
+		shouldClear = app.removedTasksBrowser.HandleInput(event)
+		
+	} else if app.columnsEditor.Visible {
+		shouldClear = app.columnsEditor.HandleInput(event)
+		
+	} else if app.boardPicker.Visible {
+		shouldClear = app.boardPicker.HandleInput(event)
+		
+		if !app.boardPicker.Visible && app.boardPicker.Result != "" {
+			// A moved task isn't on the board anymore, while a copied one stays in focus.
+			if _, columnIndex := app.board.GetColumnForTask(app.tasksView.TaskInFocus); columnIndex == -1 {
+				app.tasksView.SetDefaultFocusedWidget()
+			}
+			
+			app.notificationPopup.SetMessage(app.boardPicker.Result)
+			app.notificationPopup.Show()
+		}
+		
 	} else { // Default view is the tasks-view (the board itself)
 		switch event.ID {
 		case "?":
@@ -69,21 +103,64 @@ func (app *App) handleKeymap(event termui.Event) bool {
 				app.createTaskPopup.Show()
 			}
 			
+		case "<Enter>":
+			if app.tasksView.TaskInFocus != nil {
+				app.taskDetailsPopup.SetTask(app.tasksView.TaskInFocus)
+				app.taskDetailsPopup.Show()
+			}
+			
 		case "d":
-			if !app.confirmationPopup.Visible {
+			if app.tasksView.TaskInFocus != nil && !app.confirmationPopup.Visible {
+				task := app.tasksView.TaskInFocus
 				action := func(choice bool) {
 					if choice == false {
 						return
 					}
 					
-					app.userConfig.DeleteTask(app.boardName, app.tasksView.TaskInFocus)
+					err := app.userConfig.DeleteTask(app.boardId, task)
+					if err != nil {
+						app.reportError(fmt.Errorf("Failed to move the task to the trash. %s", err))
+						return
+					}
 					app.tasksView.SetDefaultFocusedWidget()
 				}
 				
-				app.confirmationPopup.SetMessageAndAction("Are you sure you want to delete this task?", action)
+				app.confirmationPopup.SetMessageAndAction("Are you sure you want to move this task to the trash?", action)
 				app.confirmationPopup.Show()
 			}
 			
+		case "a":
+			if app.tasksView.TaskInFocus != nil {
+				err := app.userConfig.ArchiveTask(app.boardId, app.tasksView.TaskInFocus)
+				if err != nil {
+					app.notificationPopup.SetMessage(err.Error())
+					app.notificationPopup.Show()
+				} else {
+					app.tasksView.SetDefaultFocusedWidget()
+				}
+				shouldClear = true
+			}
+			
+		case "t":
+			app.removedTasksBrowser.Show()
+			
+		case "C":
+			app.columnsEditor.Show()
+			
+		case "m":
+			if app.tasksView.TaskInFocus != nil {
+				app.boardPicker.Show(app.tasksView.TaskInFocus)
+			}
+			
+		case "]", "[", "}", "{":
+			shouldClear = app.moveTaskInFocus(event.ID)
+			
+		case "u":
+			shouldClear = app.undoOrRedo(app.userConfig.Undo)
+			
+		case "<C-r>":
+			shouldClear = app.undoOrRedo(app.userConfig.Redo)
+			
 		default: // Handles the movements/action in the board view itself
 			shouldClear = app.tasksView.HandleKeymap(event.ID)
 			
@@ -93,3 +170,149 @@ func (app *App) handleKeymap(event termui.Event) bool {
 	return shouldClear
 }
 
+// moveTaskInFocus moves the task in focus a column right on "]" or left on "[", and
+// "}" and "{" land it at the bottom of the column. Moves that look like a mistake are
+// confirmed first. It returns a flag indicating if we should clear before the next render.
+func (app *App) moveTaskInFocus(key string) bool {
+	task := app.tasksView.TaskInFocus
+	if task == nil {
+		return app.tasksView.HandleKeymap(key)
+	}
+	
+	_, columnIndex := app.board.GetColumnForTask(task)
+	forward := key == "]" || key == "}"
+	targetIndex := utils.Cond(forward, columnIndex + 1, columnIndex - 1)
+	if columnIndex == -1 || targetIndex < 0 || targetIndex >= len(app.board.Columns) {
+		return app.tasksView.HandleKeymap(key)
+	}
+	
+	targetColumn := app.board.Columns[targetIndex]
+	reasons := []string{}
+	
+	if forward && app.board.IsBlocked(task) {
+		reasons = append(reasons, fmt.Sprintf("This task is blocked by %d open tasks.", len(app.board.GetOpenBlockers(task))))
+	}
+	
+	if app.board.WouldBreakWipLimit(targetColumn) {
+		limitReason := fmt.Sprintf("%s is at its WIP limit of %d.", targetColumn.Name, targetColumn.WipLimit)
+		
+		if app.board.StrictWipLimits {
+			app.notificationPopup.SetMessage(limitReason)
+			app.notificationPopup.Show()
+			return true
+		}
+		
+		reasons = append(reasons, limitReason)
+	}
+	
+	if len(reasons) == 0 {
+		return app.tasksView.HandleKeymap(key)
+	}
+	
+	action := func(choice bool) {
+		if choice == false {
+			return
+		}
+		
+		app.tasksView.HandleKeymap(key)
+	}
+	
+	app.confirmationPopup.SetMessageAndAction(strings.Join(reasons, " ") + " Move it anyway?", action)
+	app.confirmationPopup.Show()
+	
+	return true
+}
+
+// searchTasks filters the board by what was searched for. Searching for the key of
+// a task, like "API-42", jumps to that task instead.
+func (app *App) searchTasks(query string) {
+	taskOpt := app.board.GetTaskByKey(query)
+	if taskOpt.IsSome() {
+		app.tasksView.SetTextFilter("")
+		app.tasksView.FocusTaskById(taskOpt.Unwrap().Id)
+		return
+	}
+	
+	app.tasksView.SetTextFilter(query)
+}
+
+// undoOrRedo runs the given undo or redo on the board and focuses the task it changed.
+// It returns a flag indicating if we should clear before the next render.
+func (app *App) undoOrRedo(run func(board *domain.Board) (opt.Option[*domain.Command], error)) bool {
+	commandOpt, err := run(app.board)
+	if err != nil {
+		app.reportError(fmt.Errorf("Failed to undo or redo. %s", err))
+		return true
+	}
+	
+	if commandOpt.IsNone() {
+		return false
+	}
+	
+	command := commandOpt.Unwrap()
+	if len(command.Changes) > 0 {
+		app.tasksView.FocusTaskById(command.Changes[0].TaskId)
+	}
+	
+	return true
+}
+
+// handleBoardChanged reloads the board in place if it was saved by someone else,
+// keeping the task in focus. A notice is shown if there was something in progress
+// that the reload could conflict with.
+// It returns a flag indicating if we should clear before the next render.
+func (app *App) handleBoardChanged() bool {
+	changed, err := app.userConfig.HasBoardChanged(app.board)
+	if err != nil {
+		utils.SaveLog(utils.Error, "Failed to check if the board changed. " + err.Error(), nil)
+		return false
+	}
+	
+	if !changed {
+		return false
+	}
+	
+	focusedTaskId := ""
+	if app.tasksView.TaskInFocus != nil {
+		focusedTaskId = app.tasksView.TaskInFocus.Id
+	}
+	
+	err = app.userConfig.ReloadBoard(app.board)
+	if err != nil {
+		utils.SaveLog(utils.Error, "Failed to reload the board. " + err.Error(), nil)
+		return false
+	}
+	
+	app.tasksView.FocusTaskById(focusedTaskId)
+	app.taskDetailsPopup.RebindTask()
+	app.removedTasksBrowser.Refresh()
+	app.columnsEditor.Refresh()
+	app.boardPicker.RebindTask()
+	
+	if app.createTaskPopup.Visible {
+		if app.createTaskPopup.RebindEditingTask(app.board) {
+			app.notificationPopup.SetMessage(
+				"This board was changed outside of gotasks and got reloaded.\n" +
+				"What you typed is kept, saving it replaces the task's outside changes.",
+			)
+		} else {
+			app.notificationPopup.SetMessage(
+				"This board was changed outside of gotasks and got reloaded.\n" +
+				"The task being edited was deleted, so it can't be saved anymore.",
+			)
+		}
+		app.notificationPopup.Show()
+		
+	} else if app.confirmationPopup.Visible {
+		// The task the confirmation was asked for might not be there anymore.
+		app.confirmationPopup.Hide()
+		
+		app.notificationPopup.SetMessage(
+			"This board was changed outside of gotasks and got reloaded.\n" +
+			"Nothing was done, please try again.",
+		)
+		app.notificationPopup.Show()
+	}
+	
+	return true
+}
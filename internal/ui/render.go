@@ -22,5 +22,22 @@ func (app *App) render(shouldClear bool) {
 	} else if app.searchDialogPopup.Visible {
 		app.searchDialogPopup.Draw()
 		
+	} else if app.taskDetailsPopup.Visible {
+		app.taskDetailsPopup.Draw()
+		
+	} else if app.removedTasksBrowser.Visible {
+		app.removedTasksBrowser.Draw()
+		
+	} else if app.columnsEditor.Visible {
+		app.columnsEditor.Draw()
+		
+	} else if app.boardPicker.Visible {
+		app.boardPicker.Draw()
+		
+	}
+	
+	// Notices go on top of everything else.
+	if app.notificationPopup.Visible {
+		app.notificationPopup.Draw()
 	}
 }
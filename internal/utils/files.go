@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes the content to a temporary file next to the given path,
+// syncs it to disk and only then renames it over the original. Readers, or a crash
+// half way through, see either the old file or the new one but never a partial one.
+func WriteFileAtomic(filePath string, content []byte, perm os.FileMode) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), "." + filepath.Base(filePath) + "-*.tmp")
+	if err != nil {
+		return err
+	}
+	tempFilePath := tempFile.Name()
+
+	// Make sure the temporary file doesn't stay around if anything fails.
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			tempFile.Close()
+			os.Remove(tempFilePath)
+		}
+	}()
+
+	_, err = tempFile.Write(content)
+	if err != nil {
+		return err
+	}
+
+	err = tempFile.Sync()
+	if err != nil {
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tempFilePath, perm)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tempFilePath, filePath)
+	if err != nil {
+		return err
+	}
+
+	succeeded = true
+
+	return nil
+}
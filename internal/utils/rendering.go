@@ -1,6 +1,10 @@
 package utils
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // TextEllipsis checks if the text is longer than the width of its container and
 // adds a "..." as the last characters accordingly.
@@ -36,4 +40,29 @@ func CenterText(text string, width int, withBorders bool) string {
 
 	// Return the padded text
 	return leftPadding + text + rightPadding
-}
\ No newline at end of file
+}
+
+// FormatTime shows a time in the local time zone.
+func FormatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// FormatAge shows a duration in its biggest unit, like "3d" or "5h".
+func FormatAge(duration time.Duration) string {
+	day := 24 * time.Hour
+	
+	switch {
+	case duration < time.Minute:
+		return "now"
+	case duration < time.Hour:
+		return fmt.Sprintf("%dm", duration / time.Minute)
+	case duration < day:
+		return fmt.Sprintf("%dh", duration / time.Hour)
+	case duration < 14 * day:
+		return fmt.Sprintf("%dd", duration / day)
+	case duration < 365 * day:
+		return fmt.Sprintf("%dw", duration / (7 * day))
+	default:
+		return fmt.Sprintf("%dy", duration / (365 * day))
+	}
+}
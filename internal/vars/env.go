@@ -4,4 +4,5 @@ const (
 	DebugFlag = "GOTASKS_DEBUG"
 	ThemeFlag = "GOTASKS_THEME"
 	EditorOfChoice = "EDITOR"
+	StoreFlag = "GOTASKS_STORE"
 )
\ No newline at end of file